@@ -0,0 +1,75 @@
+package allocators
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// ErrNotSupported is returned by allocators
+// that can't release separate allocations
+var ErrNotSupported = errors.New("operation is not supported")
+
+// Allocator is a common interface of custom allocators,
+// memory returned by them is invisible for GC, so it
+// must be used only for values without Go pointers
+type Allocator interface {
+	Allocate(size int) (unsafe.Pointer, error)
+	AllocateAligned(size int, align int) (unsafe.Pointer, error)
+	Deallocate(pointer unsafe.Pointer) error
+	Reset()
+	Stats() Stats
+}
+
+type Stats struct {
+	Capacity int
+	Used     int
+
+	// recorded only by StatsAllocator
+	Allocations       int
+	Deallocations     int
+	FailedAllocations int
+	LiveAllocations   int
+	PeakUsed          int
+	RequestedBytes    int // total requested by callers
+	ConsumedBytes     int // total consumed including padding and headers
+}
+
+// New allocates zeroed memory for a value of type T
+// respecting its size and alignment
+func New[T any](a Allocator) (*T, error) {
+	var zero T
+	pointer, err := a.AllocateAligned(int(unsafe.Sizeof(zero)), int(unsafe.Alignof(zero)))
+	if err != nil {
+		return nil, err
+	}
+
+	value := (*T)(pointer)
+	*value = zero
+	return value, nil
+}
+
+func Store[T any](pointer unsafe.Pointer, value T) {
+	*(*T)(pointer) = value
+}
+
+func Load[T any](pointer unsafe.Pointer) T {
+	return *(*T)(pointer)
+}
+
+func isPowerOfTwo(value int) bool {
+	return value > 0 && value&(value-1) == 0
+}
+
+// alignUp returns the first offset starting from offset
+// with address of data[offset] multiple of align
+func alignUp(data []byte, offset int, align int) int {
+	address := uintptr(unsafe.Pointer(unsafe.SliceData(data))) + uintptr(offset)
+	padding := (align - int(address&uintptr(align-1))) & (align - 1)
+	return offset + padding
+}
+
+func alignedBuffer(size int, align int) []byte {
+	buffer := make([]byte, size+align-1)
+	offset := alignUp(buffer, 0, align)
+	return buffer[offset : offset+size : offset+size]
+}
@@ -0,0 +1,78 @@
+package allocators_test
+
+import (
+	"testing"
+
+	"golang_course/lessons/allocator/allocators"
+	"golang_course/lessons/allocator/allocators/allocatortest"
+)
+
+// go test -bench=. -benchmem
+
+const capacity = 1 << 12
+
+var factories = map[string]allocatortest.Factory{
+	"Linear": func() allocators.Allocator {
+		allocator, _ := allocators.NewLinearAllocator(capacity)
+		return allocator
+	},
+	"ChunkedLinear": func() allocators.Allocator {
+		allocator, _ := allocators.NewChunkedLinearAllocator(capacity / 4)
+		return allocator
+	},
+	"Stack": func() allocators.Allocator {
+		allocator, _ := allocators.NewStackAllocator(capacity)
+		return allocator
+	},
+	"Pool": func() allocators.Allocator {
+		allocator, _ := allocators.NewPoolAllocator(capacity, 64)
+		return allocator
+	},
+	"CheckedPool": func() allocators.Allocator {
+		allocator, _ := allocators.NewCheckedPoolAllocator(capacity, 64, true)
+		return allocator
+	},
+	"Buddy": func() allocators.Allocator {
+		allocator, _ := allocators.NewBuddyAllocator(capacity, 16)
+		return allocator
+	},
+	"ConcurrentLinear": func() allocators.Allocator {
+		allocator, _ := allocators.NewConcurrentLinearAllocator(capacity)
+		return allocator
+	},
+	"ConcurrentPool": func() allocators.Allocator {
+		allocator, _ := allocators.NewConcurrentPoolAllocator(capacity, 64)
+		return allocator
+	},
+	"ShardedPool": func() allocators.Allocator {
+		allocator, _ := allocators.NewShardedPoolAllocator(capacity, 64)
+		return allocator
+	},
+	"SynchronizedBuddy": func() allocators.Allocator {
+		allocator, _ := allocators.NewBuddyAllocator(capacity, 16)
+		return allocators.NewSynchronizedAllocator(allocator)
+	},
+	"SizeClass": func() allocators.Allocator {
+		return allocators.NewSizeClassAllocator()
+	},
+	"StatsStack": func() allocators.Allocator {
+		allocator, _ := allocators.NewStackAllocator(capacity)
+		return allocators.NewStatsAllocator(allocator)
+	},
+}
+
+func TestConformance(t *testing.T) {
+	for name, factory := range factories {
+		t.Run(name, func(t *testing.T) {
+			allocatortest.TestAllocator(t, factory)
+		})
+	}
+}
+
+func BenchmarkAllocators(b *testing.B) {
+	for name, factory := range factories {
+		b.Run(name, func(b *testing.B) {
+			allocatortest.BenchmarkAllocator(b, factory)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Package allocatortest implements a conformance test suite
+// and benchmarks for implementations of allocators.Allocator
+package allocatortest
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+
+	"golang_course/lessons/allocator/allocators"
+)
+
+// Factory must return a new allocator with at least
+// 1 KB of capacity able to serve 64-byte allocations
+type Factory func() allocators.Allocator
+
+const objectSize = 64
+
+// TestAllocator runs all conformance checks for allocators created by factory
+func TestAllocator(t *testing.T, factory Factory) {
+	t.Run("IncorrectArguments", func(t *testing.T) { testIncorrectArguments(t, factory()) })
+	t.Run("DistinctMemory", func(t *testing.T) { testDistinctMemory(t, factory()) })
+	t.Run("Alignment", func(t *testing.T) { testAlignment(t, factory()) })
+	t.Run("Deallocate", func(t *testing.T) { testDeallocate(t, factory()) })
+	t.Run("Reset", func(t *testing.T) { testReset(t, factory()) })
+}
+
+func testIncorrectArguments(t *testing.T, allocator allocators.Allocator) {
+	if _, err := allocator.Allocate(0); err == nil {
+		t.Error("allocation of 0 bytes must fail")
+	}
+
+	if _, err := allocator.Allocate(-1); err == nil {
+		t.Error("allocation of negative size must fail")
+	}
+
+	if _, err := allocator.AllocateAligned(8, 3); err == nil {
+		t.Error("allocation with alignment 3 must fail")
+	}
+
+	if err := allocator.Deallocate(nil); err == nil {
+		t.Error("deallocation of nil must fail")
+	}
+}
+
+func testDistinctMemory(t *testing.T, allocator allocators.Allocator) {
+	pointers := allocateAll(t, allocator, 8)
+	for idx, pointer := range pointers {
+		fill(pointer, byte(idx+1))
+	}
+
+	for idx, pointer := range pointers {
+		if !filled(pointer, byte(idx+1)) {
+			t.Fatalf("allocation %d was overwritten", idx)
+		}
+	}
+}
+
+func testAlignment(t *testing.T, allocator allocators.Allocator) {
+	for _, align := range []int{1, 2, 4, 8} {
+		pointer, err := allocator.AllocateAligned(objectSize, align)
+		if err != nil {
+			t.Fatalf("aligned allocation failed: %v", err)
+		}
+
+		if uintptr(pointer)%uintptr(align) != 0 {
+			t.Errorf("pointer %p is not aligned to %d", pointer, align)
+		}
+	}
+
+	value, err := allocators.New[int64](allocator)
+	if err != nil {
+		t.Fatalf("typed allocation failed: %v", err)
+	}
+
+	if uintptr(unsafe.Pointer(value))%unsafe.Alignof(*value) != 0 || *value != 0 {
+		t.Errorf("typed allocation is not aligned or not zeroed")
+	}
+}
+
+func testDeallocate(t *testing.T, allocator allocators.Allocator) {
+	pointer, err := allocator.Allocate(objectSize)
+	if err != nil {
+		t.Fatalf("allocation failed: %v", err)
+	}
+
+	used := allocator.Stats().Used
+	err = allocator.Deallocate(pointer)
+	if errors.Is(err, allocators.ErrNotSupported) {
+		t.Skip("deallocation is not supported")
+	} else if err != nil {
+		t.Fatalf("deallocation failed: %v", err)
+	}
+
+	if allocator.Stats().Used >= used {
+		t.Errorf("used memory wasn't decreased after deallocation")
+	}
+
+	if _, err := allocator.Allocate(objectSize); err != nil {
+		t.Errorf("allocation after deallocation failed: %v", err)
+	}
+}
+
+func testReset(t *testing.T, allocator allocators.Allocator) {
+	count := len(allocateAll(t, allocator, 0))
+	if count == 0 {
+		t.Fatal("nothing was allocated")
+	}
+
+	stats := allocator.Stats()
+	if stats.Used <= 0 || stats.Used > stats.Capacity {
+		t.Errorf("incorrect stats: %+v", stats)
+	}
+
+	allocator.Reset()
+	if used := allocator.Stats().Used; used != 0 {
+		t.Errorf("used memory after reset: %d", used)
+	}
+
+	if again := len(allocateAll(t, allocator, 0)); again < count {
+		t.Errorf("allocated %d objects after reset, expected at least %d", again, count)
+	}
+}
+
+// allocateAll allocates objects until allocator is
+// exhausted or limit (if it is positive) is reached
+func allocateAll(t *testing.T, allocator allocators.Allocator, limit int) []unsafe.Pointer {
+	const maxCount = 1 << 10
+
+	var pointers []unsafe.Pointer
+	for len(pointers) < maxCount && (limit <= 0 || len(pointers) < limit) {
+		pointer, err := allocator.Allocate(objectSize)
+		if err != nil {
+			break
+		}
+
+		pointers = append(pointers, pointer)
+	}
+
+	if limit > 0 && len(pointers) < limit {
+		t.Fatalf("allocated %d objects, expected %d", len(pointers), limit)
+	}
+
+	return pointers
+}
+
+func fill(pointer unsafe.Pointer, value byte) {
+	memory := unsafe.Slice((*byte)(pointer), objectSize)
+	for idx := range memory {
+		memory[idx] = value
+	}
+}
+
+func filled(pointer unsafe.Pointer, value byte) bool {
+	for _, memory := range unsafe.Slice((*byte)(pointer), objectSize) {
+		if memory != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// BenchmarkAllocator measures allocation of a batch of objects
+// followed by deallocation (if it is supported) and reset
+func BenchmarkAllocator(b *testing.B, factory Factory) {
+	const batchSize = 8
+
+	allocator := factory()
+	pointers := make([]unsafe.Pointer, batchSize)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range pointers {
+			pointers[j], _ = allocator.Allocate(objectSize)
+		}
+
+		for j := len(pointers) - 1; j >= 0; j-- {
+			_ = allocator.Deallocate(pointers[j])
+		}
+
+		allocator.Reset()
+	}
+}
@@ -0,0 +1,185 @@
+package allocators
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// region is aligned to this size (or to its capacity if it is
+// smaller), so blocks are aligned to their size up to this limit
+const buddyRegionAlign = 4096
+
+// BuddyAllocator splits the region into blocks of power-of-two sizes
+// and merges free neighbour blocks (buddies) back on deallocation
+type BuddyAllocator struct {
+	data         []byte
+	minBlockSize int
+	maxOrder     int
+	used         int
+
+	// free blocks of each order, block is identified
+	// by its index in units of minBlockSize
+	freeLists [][]int32
+
+	// state of blocks by the index of their first unit
+	blockOrders []int8
+	freeBlocks  []bool
+	usedBlocks  []bool
+	positions   []int32 // position in free list
+}
+
+func NewBuddyAllocator(capacity int, minBlockSize int) (*BuddyAllocator, error) {
+	if !isPowerOfTwo(capacity) || !isPowerOfTwo(minBlockSize) || minBlockSize > capacity {
+		return nil, errors.New("incorrect arguments")
+	}
+
+	maxOrder := 0
+	for minBlockSize<<maxOrder < capacity {
+		maxOrder++
+	}
+
+	units := capacity / minBlockSize
+	allocator := &BuddyAllocator{
+		data:         alignedBuffer(capacity, min(capacity, buddyRegionAlign)),
+		minBlockSize: minBlockSize,
+		maxOrder:     maxOrder,
+		freeLists:    make([][]int32, maxOrder+1),
+		blockOrders:  make([]int8, units),
+		freeBlocks:   make([]bool, units),
+		usedBlocks:   make([]bool, units),
+		positions:    make([]int32, units),
+	}
+
+	allocator.Reset()
+	return allocator, nil
+}
+
+func (a *BuddyAllocator) Allocate(size int) (unsafe.Pointer, error) {
+	return a.AllocateAligned(size, 1)
+}
+
+func (a *BuddyAllocator) AllocateAligned(size int, align int) (unsafe.Pointer, error) {
+	if size <= 0 || size > len(a.data) {
+		return nil, errors.New("incorrect size")
+	}
+
+	if !isPowerOfTwo(align) || align > min(len(a.data), buddyRegionAlign) {
+		return nil, errors.New("incorrect alignment")
+	}
+
+	// block is aligned to its size
+	order := a.order(max(size, align))
+
+	freeOrder := order
+	for freeOrder <= a.maxOrder && len(a.freeLists[freeOrder]) == 0 {
+		freeOrder++
+	}
+
+	if freeOrder > a.maxOrder {
+		return nil, errors.New("not enough memory")
+	}
+
+	block := a.freeLists[freeOrder][len(a.freeLists[freeOrder])-1]
+	a.removeFree(block, freeOrder)
+
+	for freeOrder > order {
+		freeOrder--
+		a.pushFree(block+1<<freeOrder, freeOrder)
+	}
+
+	a.blockOrders[block] = int8(order)
+	a.usedBlocks[block] = true
+	a.used += a.minBlockSize << order
+
+	pointer := unsafe.Pointer(&a.data[int(block)*a.minBlockSize])
+	return pointer, nil
+}
+
+func (a *BuddyAllocator) Deallocate(pointer unsafe.Pointer) error {
+	if pointer == nil {
+		return errors.New("incorrect pointer")
+	}
+
+	distance := uintptr(pointer) - uintptr(unsafe.Pointer(unsafe.SliceData(a.data)))
+	if distance >= uintptr(len(a.data)) {
+		return ErrForeignPointer
+	}
+
+	block := int32(distance / uintptr(a.minBlockSize))
+	if a.freeBlocks[block] {
+		return ErrDoubleFree
+	} else if distance%uintptr(a.minBlockSize) != 0 || !a.usedBlocks[block] {
+		return ErrMisalignedPointer
+	}
+
+	order := int(a.blockOrders[block])
+	a.usedBlocks[block] = false
+	a.used -= a.minBlockSize << order
+
+	for order < a.maxOrder {
+		buddy := block ^ 1<<order
+		if !a.freeBlocks[buddy] || int(a.blockOrders[buddy]) != order {
+			break
+		}
+
+		a.removeFree(buddy, order)
+		block = min(block, buddy)
+		order++
+	}
+
+	a.pushFree(block, order)
+	return nil
+}
+
+func (a *BuddyAllocator) Reset() {
+	for order := range a.freeLists {
+		a.freeLists[order] = a.freeLists[order][:0]
+	}
+
+	clear(a.freeBlocks)
+	clear(a.usedBlocks)
+	a.used = 0
+
+	a.pushFree(0, a.maxOrder)
+}
+
+// Free is the same as Reset, there is no additional memory to release
+func (a *BuddyAllocator) Free() {
+	a.Reset()
+}
+
+func (a *BuddyAllocator) Stats() Stats {
+	return Stats{
+		Capacity: len(a.data),
+		Used:     a.used,
+	}
+}
+
+// order returns the smallest order of block that fits size
+func (a *BuddyAllocator) order(size int) int {
+	order := 0
+	for a.minBlockSize<<order < size {
+		order++
+	}
+
+	return order
+}
+
+func (a *BuddyAllocator) pushFree(block int32, order int) {
+	a.blockOrders[block] = int8(order)
+	a.freeBlocks[block] = true
+	a.positions[block] = int32(len(a.freeLists[order]))
+	a.freeLists[order] = append(a.freeLists[order], block)
+}
+
+// removeFree removes block from its free list by swapping with the last one
+func (a *BuddyAllocator) removeFree(block int32, order int) {
+	list := a.freeLists[order]
+	position := a.positions[block]
+	last := list[len(list)-1]
+
+	list[position] = last
+	a.positions[last] = position
+	a.freeLists[order] = list[:len(list)-1]
+	a.freeBlocks[block] = false
+}
@@ -0,0 +1,108 @@
+package allocators
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuddyAllocatorArguments(t *testing.T) {
+	_, err := NewBuddyAllocator(1000, 16)
+	assert.Error(t, err)
+	_, err = NewBuddyAllocator(1024, 24)
+	assert.Error(t, err)
+	_, err = NewBuddyAllocator(16, 32)
+	assert.Error(t, err)
+
+	allocator, err := NewBuddyAllocator(1024, 16)
+	require.NoError(t, err)
+	assert.Equal(t, 6, allocator.maxOrder)
+
+	_, err = allocator.Allocate(2048)
+	assert.Error(t, err)
+}
+
+func TestBuddyAllocatorSplitAndCoalesce(t *testing.T) {
+	allocator, err := NewBuddyAllocator(1024, 16)
+	require.NoError(t, err)
+
+	pointer1, err := allocator.Allocate(1)
+	require.NoError(t, err)
+	assert.Equal(t, 16, allocator.Stats().Used)
+
+	// 100 bytes are rounded up to 128
+	pointer2, err := allocator.Allocate(100)
+	require.NoError(t, err)
+	assert.Equal(t, 144, allocator.Stats().Used)
+	assert.Zero(t, (uintptr(pointer2)-uintptr(pointer1))%128)
+
+	_, err = allocator.Allocate(1024)
+	assert.Error(t, err)
+
+	require.NoError(t, allocator.Deallocate(pointer2))
+	require.NoError(t, allocator.Deallocate(pointer1))
+	assert.Equal(t, 0, allocator.Stats().Used)
+
+	// all blocks are merged back
+	pointer, err := allocator.Allocate(1024)
+	require.NoError(t, err)
+	assert.Equal(t, pointer1, pointer)
+}
+
+func TestBuddyAllocatorFragmentation(t *testing.T) {
+	allocator, err := NewBuddyAllocator(1024, 16)
+	require.NoError(t, err)
+
+	pointers := make([]unsafe.Pointer, 4)
+	for idx := range pointers {
+		pointers[idx], err = allocator.Allocate(256)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, allocator.Deallocate(pointers[0]))
+	require.NoError(t, allocator.Deallocate(pointers[2]))
+
+	// 512 bytes are free, but not as neighbour buddies
+	assert.Equal(t, 512, allocator.Stats().Capacity-allocator.Stats().Used)
+	_, err = allocator.Allocate(512)
+	assert.Error(t, err)
+
+	require.NoError(t, allocator.Deallocate(pointers[1]))
+	pointer, err := allocator.Allocate(512)
+	require.NoError(t, err)
+	assert.Equal(t, pointers[0], pointer)
+}
+
+func TestBuddyAllocatorIncorrectPointers(t *testing.T) {
+	allocator, err := NewBuddyAllocator(1024, 16)
+	require.NoError(t, err)
+
+	pointer, err := allocator.Allocate(64)
+	require.NoError(t, err)
+
+	var foreign int64
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Pointer(&foreign)), ErrForeignPointer)
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Add(pointer, 1)), ErrMisalignedPointer)
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Add(pointer, 16)), ErrMisalignedPointer)
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Add(pointer, 64)), ErrDoubleFree)
+
+	require.NoError(t, allocator.Deallocate(pointer))
+	assert.ErrorIs(t, allocator.Deallocate(pointer), ErrDoubleFree)
+}
+
+func TestBuddyAllocatorAlignment(t *testing.T) {
+	allocator, err := NewBuddyAllocator(1<<16, 16)
+	require.NoError(t, err)
+
+	_, err = allocator.Allocate(16)
+	require.NoError(t, err)
+
+	pointer, err := allocator.AllocateAligned(16, 4096)
+	require.NoError(t, err)
+	assert.Zero(t, uintptr(pointer)%4096)
+
+	_, err = allocator.AllocateAligned(16, 8192)
+	assert.Error(t, err)
+}
@@ -0,0 +1,434 @@
+package allocators
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ConcurrentLinearAllocator is safe for concurrent allocations, the bump
+// pointer is moved with CAS, Reset must not be called concurrently
+type ConcurrentLinearAllocator struct {
+	data   []byte
+	offset atomic.Int64
+}
+
+func NewConcurrentLinearAllocator(capacity int) (*ConcurrentLinearAllocator, error) {
+	if capacity <= 0 {
+		return nil, errors.New("incorrect capacity")
+	}
+
+	return &ConcurrentLinearAllocator{
+		data: make([]byte, capacity),
+	}, nil
+}
+
+func (a *ConcurrentLinearAllocator) Allocate(size int) (unsafe.Pointer, error) {
+	return a.AllocateAligned(size, 1)
+}
+
+func (a *ConcurrentLinearAllocator) AllocateAligned(size int, align int) (unsafe.Pointer, error) {
+	if size <= 0 {
+		return nil, errors.New("incorrect size")
+	}
+
+	if !isPowerOfTwo(align) {
+		return nil, errors.New("incorrect alignment")
+	}
+
+	for {
+		previousOffset := a.offset.Load()
+		offset := alignUp(a.data, int(previousOffset), align)
+		if offset+size > len(a.data) {
+			return nil, errors.New("not enough memory")
+		}
+
+		if a.offset.CompareAndSwap(previousOffset, int64(offset+size)) {
+			return unsafe.Pointer(&a.data[offset]), nil
+		}
+	}
+}
+
+// Deallocate is not supported by this kind of allocator
+func (a *ConcurrentLinearAllocator) Deallocate(unsafe.Pointer) error {
+	return ErrNotSupported
+}
+
+func (a *ConcurrentLinearAllocator) Reset() {
+	a.offset.Store(0)
+}
+
+func (a *ConcurrentLinearAllocator) Stats() Stats {
+	return Stats{
+		Capacity: len(a.data),
+		Used:     int(a.offset.Load()),
+	}
+}
+
+// ConcurrentPoolAllocator is a pool with lock-free free list, Reset
+// must not be called concurrently. Links are stored outside of objects,
+// because a goroutine can read a link of the object that was just taken
+// by another goroutine (its CAS fails later, but the read races with
+// writes to the object). Head contains a version counter in high bits
+// to prevent ABA problem.
+type ConcurrentPoolAllocator struct {
+	objectPool []byte
+	objectSize int
+	links      []atomic.Uint32
+	head       atomic.Uint64
+	allocated  atomic.Int64
+}
+
+func NewConcurrentPoolAllocator(capacity int, objectSize int) (*ConcurrentPoolAllocator, error) {
+	if capacity <= 0 || objectSize <= 0 || capacity%objectSize != 0 {
+		return nil, errors.New("incorrect argumnets")
+	}
+
+	if capacity/objectSize >= endOfList {
+		return nil, errors.New("too many objects")
+	}
+
+	allocator := &ConcurrentPoolAllocator{
+		objectPool: alignedBuffer(capacity, maxAlign),
+		objectSize: objectSize,
+		links:      make([]atomic.Uint32, capacity/objectSize),
+	}
+
+	allocator.Reset()
+	return allocator, nil
+}
+
+func (a *ConcurrentPoolAllocator) Allocate(size int) (unsafe.Pointer, error) {
+	return a.AllocateAligned(size, 1)
+}
+
+func (a *ConcurrentPoolAllocator) AllocateAligned(size int, align int) (unsafe.Pointer, error) {
+	if size <= 0 || size > a.objectSize {
+		return nil, errors.New("incorrect size")
+	}
+
+	if !isPowerOfTwo(align) || align > maxAlign || a.objectSize%align != 0 {
+		return nil, errors.New("incorrect alignment")
+	}
+
+	index, err := a.allocateIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return a.pointer(index), nil
+}
+
+func (a *ConcurrentPoolAllocator) Deallocate(pointer unsafe.Pointer) error {
+	index, err := a.index(pointer)
+	if err != nil {
+		return err
+	}
+
+	a.deallocateIndex(index)
+	return nil
+}
+
+func (a *ConcurrentPoolAllocator) Reset() {
+	count := uint32(len(a.links))
+	for index := uint32(0); index < count-1; index++ {
+		a.links[index].Store(index + 1)
+	}
+
+	a.links[count-1].Store(endOfList)
+	a.head.Store(0)
+	a.allocated.Store(0)
+}
+
+func (a *ConcurrentPoolAllocator) Stats() Stats {
+	return Stats{
+		Capacity: len(a.objectPool),
+		Used:     int(a.allocated.Load()) * a.objectSize,
+	}
+}
+
+func (a *ConcurrentPoolAllocator) allocateIndex() (uint32, error) {
+	for {
+		head := a.head.Load()
+		index := uint32(head)
+		if index == endOfList {
+			// can increase capacity
+			return 0, errors.New("not enough memory")
+		}
+
+		next := a.links[index].Load()
+		version := head>>32 + 1
+		if a.head.CompareAndSwap(head, version<<32|uint64(next)) {
+			a.allocated.Add(1)
+			return index, nil
+		}
+	}
+}
+
+func (a *ConcurrentPoolAllocator) deallocateIndex(index uint32) {
+	for {
+		head := a.head.Load()
+		a.links[index].Store(uint32(head))
+
+		version := head>>32 + 1
+		if a.head.CompareAndSwap(head, version<<32|uint64(index)) {
+			a.allocated.Add(-1)
+			return
+		}
+	}
+}
+
+func (a *ConcurrentPoolAllocator) index(pointer unsafe.Pointer) (uint32, error) {
+	if pointer == nil {
+		return 0, errors.New("incorrect pointer")
+	}
+
+	distance := uintptr(pointer) - uintptr(unsafe.Pointer(unsafe.SliceData(a.objectPool)))
+	if distance >= uintptr(len(a.objectPool)) {
+		return 0, ErrForeignPointer
+	} else if distance%uintptr(a.objectSize) != 0 {
+		return 0, ErrMisalignedPointer
+	}
+
+	return uint32(distance / uintptr(a.objectSize)), nil
+}
+
+func (a *ConcurrentPoolAllocator) pointer(index uint32) unsafe.Pointer {
+	return unsafe.Pointer(&a.objectPool[int(index)*a.objectSize])
+}
+
+const (
+	shardCacheSize = 32
+	cacheLineSize  = 64
+)
+
+// poolShard is padded to avoid false sharing between shards
+type poolShard struct {
+	mutex sync.Mutex
+	cache []uint32
+	_     [cacheLineSize]byte
+}
+
+// ShardedPoolAllocator keeps small caches of free objects in shards (one
+// per P) in front of ConcurrentPoolAllocator, goroutines take the first
+// unlocked shard, so they rarely contend on the shared free list
+type ShardedPoolAllocator struct {
+	pool    *ConcurrentPoolAllocator
+	shards  []poolShard
+	counter atomic.Uint32
+
+	// objects returned to users, it's incremented after an object is
+	// taken and decremented before it's returned, so it's never bigger
+	// than the real number (counters of the pool and caches are changed
+	// separately, so their difference can count a free object as used)
+	used atomic.Int64
+}
+
+func NewShardedPoolAllocator(capacity int, objectSize int) (*ShardedPoolAllocator, error) {
+	pool, err := NewConcurrentPoolAllocator(capacity, objectSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// caches hold at most half of objects (if there are enough
+	// of them), so one shard can't take the whole pool
+	shards := make([]poolShard, runtime.GOMAXPROCS(0))
+	cacheSize := min(shardCacheSize, max(2, len(pool.links)/(2*len(shards))))
+	for idx := range shards {
+		shards[idx].cache = make([]uint32, 0, cacheSize)
+	}
+
+	return &ShardedPoolAllocator{
+		pool:   pool,
+		shards: shards,
+	}, nil
+}
+
+func (a *ShardedPoolAllocator) Allocate(size int) (unsafe.Pointer, error) {
+	return a.AllocateAligned(size, 1)
+}
+
+func (a *ShardedPoolAllocator) AllocateAligned(size int, align int) (unsafe.Pointer, error) {
+	if size <= 0 || size > a.pool.objectSize {
+		return nil, errors.New("incorrect size")
+	}
+
+	if !isPowerOfTwo(align) || align > maxAlign || a.pool.objectSize%align != 0 {
+		return nil, errors.New("incorrect alignment")
+	}
+
+	shard := a.lockShard()
+	if len(shard.cache) == 0 {
+		a.refill(shard)
+	}
+
+	if len(shard.cache) != 0 || a.steal(shard) {
+		index := shard.cache[len(shard.cache)-1]
+		shard.cache = shard.cache[:len(shard.cache)-1]
+		shard.mutex.Unlock()
+		a.used.Add(1)
+		return a.pool.pointer(index), nil
+	}
+
+	shard.mutex.Unlock()
+
+	// the rest of free objects can be cached by locked shards,
+	// they are returned to the shared free list while any is free
+	for {
+		index, err := a.pool.allocateIndex()
+		if err == nil {
+			a.used.Add(1)
+			return a.pool.pointer(index), nil
+		}
+
+		if a.used.Load() >= int64(len(a.pool.links)) {
+			return nil, err
+		}
+
+		a.drain()
+	}
+}
+
+func (a *ShardedPoolAllocator) Deallocate(pointer unsafe.Pointer) error {
+	index, err := a.pool.index(pointer)
+	if err != nil {
+		return err
+	}
+
+	a.used.Add(-1)
+	shard := a.lockShard()
+	defer shard.mutex.Unlock()
+
+	if len(shard.cache) == cap(shard.cache) {
+		a.flush(shard, cap(shard.cache)/2)
+	}
+
+	shard.cache = append(shard.cache, index)
+	return nil
+}
+
+// Reset must not be called concurrently
+func (a *ShardedPoolAllocator) Reset() {
+	for idx := range a.shards {
+		a.shards[idx].cache = a.shards[idx].cache[:0]
+	}
+
+	a.used.Store(0)
+	a.pool.Reset()
+}
+
+func (a *ShardedPoolAllocator) Stats() Stats {
+	return Stats{
+		Capacity: len(a.pool.objectPool),
+		Used:     int(a.used.Load()) * a.pool.objectSize,
+	}
+}
+
+func (a *ShardedPoolAllocator) lockShard() *poolShard {
+	start := int(a.counter.Add(1))
+	for idx := 0; idx < len(a.shards); idx++ {
+		shard := &a.shards[(start+idx)%len(a.shards)]
+		if shard.mutex.TryLock() {
+			return shard
+		}
+	}
+
+	shard := &a.shards[start%len(a.shards)]
+	shard.mutex.Lock()
+	return shard
+}
+
+// refill takes half of the cache from the shared free list
+func (a *ShardedPoolAllocator) refill(shard *poolShard) {
+	for len(shard.cache) < cap(shard.cache)/2 {
+		index, err := a.pool.allocateIndex()
+		if err != nil {
+			return
+		}
+
+		shard.cache = append(shard.cache, index)
+	}
+}
+
+// steal takes an object from the cache of another shard, shards are
+// not waited for to avoid deadlocks, so objects can be missed
+func (a *ShardedPoolAllocator) steal(shard *poolShard) bool {
+	for idx := range a.shards {
+		other := &a.shards[idx]
+		if other == shard || !other.mutex.TryLock() {
+			continue
+		}
+
+		if len(other.cache) != 0 {
+			shard.cache = append(shard.cache, other.cache[len(other.cache)-1])
+			other.cache = other.cache[:len(other.cache)-1]
+			other.mutex.Unlock()
+			return true
+		}
+
+		other.mutex.Unlock()
+	}
+
+	return false
+}
+
+// drain returns caches of all shards to the shared free list, it waits
+// for locked shards, but it never holds several locks, so can't deadlock
+func (a *ShardedPoolAllocator) drain() {
+	for idx := range a.shards {
+		shard := &a.shards[idx]
+		shard.mutex.Lock()
+		a.flush(shard, 0)
+		shard.mutex.Unlock()
+	}
+}
+
+// flush returns objects to the shared free list until
+// the cache contains only size of them
+func (a *ShardedPoolAllocator) flush(shard *poolShard, size int) {
+	for len(shard.cache) > size {
+		a.pool.deallocateIndex(shard.cache[len(shard.cache)-1])
+		shard.cache = shard.cache[:len(shard.cache)-1]
+	}
+}
+
+// SynchronizedAllocator makes any allocator safe for
+// concurrent use by guarding all calls with a mutex
+type SynchronizedAllocator struct {
+	mutex     sync.Mutex
+	allocator Allocator
+}
+
+func NewSynchronizedAllocator(allocator Allocator) *SynchronizedAllocator {
+	return &SynchronizedAllocator{allocator: allocator}
+}
+
+func (a *SynchronizedAllocator) Allocate(size int) (unsafe.Pointer, error) {
+	return a.AllocateAligned(size, 1)
+}
+
+func (a *SynchronizedAllocator) AllocateAligned(size int, align int) (unsafe.Pointer, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.allocator.AllocateAligned(size, align)
+}
+
+func (a *SynchronizedAllocator) Deallocate(pointer unsafe.Pointer) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.allocator.Deallocate(pointer)
+}
+
+func (a *SynchronizedAllocator) Reset() {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.allocator.Reset()
+}
+
+func (a *SynchronizedAllocator) Stats() Stats {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.allocator.Stats()
+}
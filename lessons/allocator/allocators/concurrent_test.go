@@ -0,0 +1,279 @@
+package allocators
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -race -run Concurrent
+// go test -bench=Parallel -benchmem -cpu=1,4,8
+
+const (
+	goroutinesCount = 8
+	iterationsCount = 1000
+)
+
+func TestConcurrentLinearAllocator(t *testing.T) {
+	allocator, err := NewConcurrentLinearAllocator(goroutinesCount * iterationsCount * 16)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	pointers := make([][]*int64, goroutinesCount)
+	for idx := 0; idx < goroutinesCount; idx++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			for i := 0; i < iterationsCount; i++ {
+				value, err := New[int64](allocator)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				*value = int64(idx*iterationsCount + i)
+				pointers[idx] = append(pointers[idx], value)
+			}
+		}(idx)
+	}
+
+	wg.Wait()
+	for idx := range pointers {
+		for i, value := range pointers[idx] {
+			assert.Equal(t, int64(idx*iterationsCount+i), *value)
+		}
+	}
+
+	_, err = allocator.Allocate(goroutinesCount * iterationsCount * 16)
+	assert.Error(t, err)
+}
+
+func TestConcurrentPoolAllocators(t *testing.T) {
+	const objectSize = 16
+	const capacity = goroutinesCount * 4 * objectSize
+
+	concurrentPool, err := NewConcurrentPoolAllocator(capacity, objectSize)
+	require.NoError(t, err)
+	shardedPool, err := NewShardedPoolAllocator(capacity, objectSize)
+	require.NoError(t, err)
+	stackAllocator, err := NewStackAllocator(capacity * 2)
+	require.NoError(t, err)
+
+	allocators := map[string]Allocator{
+		"ConcurrentPool":    concurrentPool,
+		"ShardedPool":       shardedPool,
+		"SynchronizedStack": NewSynchronizedAllocator(stackAllocator),
+	}
+
+	for name, allocator := range allocators {
+		t.Run(name, func(t *testing.T) {
+			testExclusiveOwnership(t, allocator)
+			assert.Equal(t, 0, allocator.Stats().Used)
+		})
+	}
+}
+
+// testExclusiveOwnership checks that the same object
+// is never used by several goroutines at the same time
+func testExclusiveOwnership(t *testing.T, allocator Allocator) {
+	var wg sync.WaitGroup
+	for idx := 0; idx < goroutinesCount; idx++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			for i := 0; i < iterationsCount; i++ {
+				pointer, err := allocator.Allocate(16)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				value := (*[2]int64)(pointer)
+				value[0], value[1] = int64(idx), int64(i)
+				if value[0] != int64(idx) || value[1] != int64(i) {
+					t.Errorf("object is shared between goroutines")
+				}
+
+				// stack allocator releases only the top of the stack, other
+				// errors are bugs (FailNow can't be called from goroutines)
+				err = allocator.Deallocate(pointer)
+				for errors.Is(err, ErrOutOfOrder) {
+					runtime.Gosched()
+					err = allocator.Deallocate(pointer)
+				}
+
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(idx)
+	}
+
+	wg.Wait()
+}
+
+func TestShardedPoolAllocatorSteal(t *testing.T) {
+	allocator, err := NewShardedPoolAllocator(4*16, 16)
+	require.NoError(t, err)
+
+	pointers := make([]unsafe.Pointer, 0, 4)
+	for i := 0; i < 4; i++ {
+		pointer, err := allocator.Allocate(16)
+		require.NoError(t, err)
+		pointers = append(pointers, pointer)
+	}
+
+	_, err = allocator.Allocate(16)
+	assert.Error(t, err)
+
+	// objects are cached by different shards
+	for _, pointer := range pointers {
+		require.NoError(t, allocator.Deallocate(pointer))
+	}
+
+	for i := 0; i < 4; i++ {
+		_, err := allocator.Allocate(16)
+		require.NoError(t, err)
+	}
+}
+
+// objects cached by shards locked by other goroutines are
+// also available, so the whole pool can be allocated concurrently
+func TestShardedPoolAllocatorFullCapacity(t *testing.T) {
+	const objectsPerGoroutine = 4
+	allocator, err := NewShardedPoolAllocator(goroutinesCount*objectsPerGoroutine*16, 16)
+	require.NoError(t, err)
+
+	pointers := make([][]unsafe.Pointer, goroutinesCount)
+	for round := 0; round < 100; round++ {
+		var wg sync.WaitGroup
+		for idx := 0; idx < goroutinesCount; idx++ {
+			wg.Add(1)
+			go func(idx int) {
+				defer wg.Done()
+				for i := 0; i < objectsPerGoroutine; i++ {
+					pointer, err := allocator.Allocate(16)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+
+					pointers[idx] = append(pointers[idx], pointer)
+				}
+			}(idx)
+		}
+
+		wg.Wait()
+		require.False(t, t.Failed())
+
+		_, err = allocator.Allocate(16)
+		require.Error(t, err)
+
+		// objects are returned to caches of different shards
+		for idx := 0; idx < goroutinesCount; idx++ {
+			wg.Add(1)
+			go func(idx int) {
+				defer wg.Done()
+				for _, pointer := range pointers[idx] {
+					if err := allocator.Deallocate(pointer); err != nil {
+						t.Error(err)
+					}
+				}
+
+				pointers[idx] = pointers[idx][:0]
+			}(idx)
+		}
+
+		wg.Wait()
+		require.Equal(t, 0, allocator.Stats().Used)
+	}
+}
+
+// goroutines hold all objects except the one they are reallocating,
+// so each allocation has a free object while shards refill several
+// objects at once (used objects are never overcounted)
+func TestShardedPoolAllocatorRefillAtFullCapacity(t *testing.T) {
+	const objectsPerGoroutine = 16
+	allocator, err := NewShardedPoolAllocator(goroutinesCount*objectsPerGoroutine*16, 16)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for idx := 0; idx < goroutinesCount; idx++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pointers := make([]unsafe.Pointer, 0, objectsPerGoroutine)
+			for i := 0; i < iterationsCount; i++ {
+				if len(pointers) == objectsPerGoroutine {
+					if err := allocator.Deallocate(pointers[i%objectsPerGoroutine]); err != nil {
+						t.Error(err)
+						return
+					}
+
+					pointers = append(pointers[:i%objectsPerGoroutine], pointers[i%objectsPerGoroutine+1:]...)
+				}
+
+				pointer, err := allocator.Allocate(16)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+
+				pointers = append(pointers, pointer)
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, goroutinesCount*objectsPerGoroutine*16, allocator.Stats().Used)
+}
+
+func BenchmarkParallelConcurrentPool(b *testing.B) {
+	allocator, _ := NewConcurrentPoolAllocator(1<<16, 64)
+	benchmarkParallel(b, allocator)
+}
+
+func BenchmarkParallelShardedPool(b *testing.B) {
+	allocator, _ := NewShardedPoolAllocator(1<<16, 64)
+	benchmarkParallel(b, allocator)
+}
+
+func BenchmarkParallelSynchronizedPool(b *testing.B) {
+	allocator, _ := NewPoolAllocator(1<<16, 64)
+	benchmarkParallel(b, NewSynchronizedAllocator(allocator))
+}
+
+func BenchmarkParallelSyncPool(b *testing.B) {
+	pool := sync.Pool{
+		New: func() interface{} { return new([64]byte) },
+	}
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			object := pool.Get().(*[64]byte)
+			object[0] = 1
+			pool.Put(object)
+		}
+	})
+}
+
+func benchmarkParallel(b *testing.B, allocator Allocator) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			pointer, err := allocator.Allocate(64)
+			if err != nil {
+				b.Error(err)
+				return
+			}
+
+			*(*byte)(pointer) = 1
+			_ = allocator.Deallocate(pointer)
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package allocators
+
+import (
+	"errors"
+	"unsafe"
+)
+
+type LinearAllocator struct {
+	data      []byte
+	chunks    [][]byte
+	chunkSize int
+}
+
+func NewLinearAllocator(capacity int) (*LinearAllocator, error) {
+	if capacity <= 0 {
+		return nil, errors.New("incorrect capacity")
+	}
+
+	return &LinearAllocator{
+		data: make([]byte, 0, capacity),
+	}, nil
+}
+
+// NewChunkedLinearAllocator creates an allocator that chains
+// new chunks instead of failing when the current chunk is full
+func NewChunkedLinearAllocator(chunkSize int) (*LinearAllocator, error) {
+	if chunkSize <= 0 {
+		return nil, errors.New("incorrect chunk size")
+	}
+
+	return &LinearAllocator{
+		data:      make([]byte, 0, chunkSize),
+		chunkSize: chunkSize,
+	}, nil
+}
+
+func (a *LinearAllocator) Allocate(size int) (unsafe.Pointer, error) {
+	return a.AllocateAligned(size, 1)
+}
+
+func (a *LinearAllocator) AllocateAligned(size int, align int) (unsafe.Pointer, error) {
+	if size <= 0 {
+		return nil, errors.New("incorrect size")
+	}
+
+	if !isPowerOfTwo(align) {
+		return nil, errors.New("incorrect alignment")
+	}
+
+	previousLength := alignUp(a.data, len(a.data), align)
+	newLength := previousLength + size
+
+	if newLength > cap(a.data) {
+		if a.chunkSize == 0 {
+			return nil, errors.New("not enough memory")
+		}
+
+		// previous chunks are not touched, so
+		// returned pointers stay valid
+		a.chunks = append(a.chunks, a.data)
+		a.data = make([]byte, 0, max(a.chunkSize, size+align-1))
+		previousLength = alignUp(a.data, 0, align)
+		newLength = previousLength + size
+	}
+
+	a.data = a.data[:newLength]
+	pointer := unsafe.Pointer(&a.data[previousLength])
+	return pointer, nil
+}
+
+// Deallocate is not supported by this kind of allocator
+func (a *LinearAllocator) Deallocate(unsafe.Pointer) error {
+	return ErrNotSupported
+}
+
+// Reset keeps the memory, if several chunks were used they are
+// replaced by one chunk of the same total size for the next cycle
+func (a *LinearAllocator) Reset() {
+	if len(a.chunks) != 0 {
+		capacity := cap(a.data)
+		for _, chunk := range a.chunks {
+			capacity += cap(chunk)
+		}
+
+		a.data = make([]byte, 0, capacity)
+		a.chunks = nil
+	}
+
+	a.data = a.data[:0]
+}
+
+// Free releases all chunks except the first one
+func (a *LinearAllocator) Free() {
+	if len(a.chunks) != 0 {
+		// keep only the first chunk, others will be collected
+		a.data = a.chunks[0]
+		a.chunks = nil
+	}
+
+	a.data = a.data[:0]
+}
+
+func (a *LinearAllocator) Stats() Stats {
+	stats := Stats{
+		Capacity: cap(a.data),
+		Used:     len(a.data),
+	}
+
+	for _, chunk := range a.chunks {
+		stats.Capacity += cap(chunk)
+		stats.Used += len(chunk)
+	}
+
+	return stats
+}
@@ -0,0 +1,113 @@
+package allocators
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinearAllocatorOutOfMemory(t *testing.T) {
+	allocator, err := NewLinearAllocator(8)
+	require.NoError(t, err)
+
+	_, err = allocator.Allocate(8)
+	assert.NoError(t, err)
+	_, err = allocator.Allocate(1)
+	assert.Error(t, err)
+
+	allocator.Free()
+	_, err = allocator.Allocate(8)
+	assert.NoError(t, err)
+}
+
+func TestChunkedLinearAllocator(t *testing.T) {
+	_, err := NewChunkedLinearAllocator(0)
+	assert.Error(t, err)
+
+	allocator, err := NewChunkedLinearAllocator(16)
+	require.NoError(t, err)
+
+	pointers := make([]unsafe.Pointer, 0, 10)
+	for i := 0; i < 10; i++ {
+		pointer, err := allocator.Allocate(8)
+		require.NoError(t, err)
+		Store[int64](pointer, int64(i))
+		pointers = append(pointers, pointer)
+	}
+
+	assert.Len(t, allocator.chunks, 4)
+
+	big, err := allocator.Allocate(100)
+	require.NoError(t, err)
+	assert.Equal(t, 100, cap(allocator.data))
+	Store[[100]byte](big, [100]byte{99: 1})
+
+	for i, pointer := range pointers {
+		assert.Equal(t, int64(i), Load[int64](pointer))
+	}
+
+	first := unsafe.SliceData(allocator.chunks[0])
+	allocator.Free()
+	assert.Empty(t, allocator.chunks)
+	assert.Equal(t, 0, len(allocator.data))
+	assert.Equal(t, first, unsafe.SliceData(allocator.data))
+}
+
+func TestLinearAllocatorAlignment(t *testing.T) {
+	allocator, err := NewLinearAllocator(64)
+	require.NoError(t, err)
+
+	_, err = allocator.AllocateAligned(4, 3)
+	assert.Error(t, err)
+
+	_, err = allocator.Allocate(1)
+	require.NoError(t, err)
+
+	pointer, err := allocator.AllocateAligned(4, 4)
+	require.NoError(t, err)
+	assert.Zero(t, uintptr(pointer)%4)
+
+	_, err = allocator.Allocate(1)
+	require.NoError(t, err)
+
+	value, err := New[int64](allocator)
+	require.NoError(t, err)
+	assert.Zero(t, uintptr(unsafe.Pointer(value))%unsafe.Alignof(*value))
+	assert.Zero(t, *value)
+}
+
+func TestChunkedLinearAllocatorAlignment(t *testing.T) {
+	allocator, err := NewChunkedLinearAllocator(9)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, err = allocator.Allocate(1)
+		require.NoError(t, err)
+
+		value, err := New[int64](allocator)
+		require.NoError(t, err)
+		assert.Zero(t, uintptr(unsafe.Pointer(value))%unsafe.Alignof(*value))
+	}
+}
+
+func TestChunkedLinearAllocatorReset(t *testing.T) {
+	allocator, err := NewChunkedLinearAllocator(16)
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		_, err = allocator.Allocate(16)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, Stats{Capacity: 64, Used: 64}, allocator.Stats())
+
+	allocator.Reset()
+	assert.Empty(t, allocator.chunks)
+	assert.Equal(t, Stats{Capacity: 64, Used: 0}, allocator.Stats())
+
+	_, err = allocator.Allocate(64)
+	require.NoError(t, err)
+	assert.Empty(t, allocator.chunks)
+}
@@ -0,0 +1,265 @@
+package allocators
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"unsafe"
+)
+
+// objectPool is aligned to maxAlign, so slots are
+// aligned to any alignment that divides objectSize
+const maxAlign = 8
+
+// free objects store index of the next free object
+// in their first bytes (intrusive linked list)
+const (
+	linkSize  = 4
+	endOfList = math.MaxUint32
+)
+
+// objects smaller than linkSize store narrower links (2 or 1 bytes),
+// so their number is limited to 65534 or 254, the maximum value of
+// a link marks the end of the list
+func linkWidth(objectSize int) int {
+	switch {
+	case objectSize >= linkSize:
+		return linkSize
+	case objectSize >= 2:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func linkLimit(objectSize int) uint32 {
+	return uint32(1<<(8*linkWidth(objectSize)) - 1)
+}
+
+// freed objects are filled with this pattern
+// in checked mode to detect use after free
+const poisonPattern = 0xDD
+
+var (
+	ErrForeignPointer    = errors.New("pointer is outside of object pool")
+	ErrMisalignedPointer = errors.New("pointer is not at object start")
+	ErrDoubleFree        = errors.New("object is already free")
+	ErrUseAfterFree      = errors.New("free object was modified")
+)
+
+type PoolAllocator struct {
+	objectPool []byte
+	objectSize int
+	head       uint32
+	allocated  int
+
+	// used only in checked mode
+	checked     bool
+	poison      bool
+	freeObjects []bool
+}
+
+func NewPoolAllocator(capacity int, objectSize int) (*PoolAllocator, error) {
+	if capacity <= 0 || objectSize <= 0 || capacity%objectSize != 0 {
+		return nil, errors.New("incorrect argumnets")
+	}
+
+	if capacity/objectSize >= int(linkLimit(objectSize)) {
+		return nil, errors.New("too many objects")
+	}
+
+	allocator := &PoolAllocator{
+		objectPool: alignedBuffer(capacity, maxAlign),
+		objectSize: objectSize,
+	}
+
+	allocator.resetMemoryState()
+	return allocator, nil
+}
+
+// NewCheckedPoolAllocator creates an allocator for debugging that validates
+// deallocated pointers and optionally poisons free objects
+func NewCheckedPoolAllocator(capacity int, objectSize int, poison bool) (*PoolAllocator, error) {
+	allocator, err := NewPoolAllocator(capacity, objectSize)
+	if err != nil {
+		return nil, err
+	}
+
+	allocator.checked = true
+	allocator.poison = poison
+	allocator.freeObjects = make([]bool, capacity/objectSize)
+
+	allocator.resetMemoryState()
+	return allocator, nil
+}
+
+// Allocate returns an object if size fits into it
+func (a *PoolAllocator) Allocate(size int) (unsafe.Pointer, error) {
+	return a.AllocateAligned(size, 1)
+}
+
+func (a *PoolAllocator) allocate() (unsafe.Pointer, error) {
+	if a.head == endOfList {
+		// can increase capacity
+		return nil, errors.New("not enough memory")
+	}
+
+	index := a.head
+	a.head = a.next(index)
+
+	if a.checked {
+		a.freeObjects[index] = false
+		if a.head != endOfList && (int(a.head) >= len(a.freeObjects) || !a.freeObjects[a.head]) {
+			// corrupted link, rest of the free list is lost
+			a.head = endOfList
+			return nil, ErrUseAfterFree
+		}
+
+		if !a.isPoisoned(index) {
+			// corrupted object is not returned to the pool
+			return nil, ErrUseAfterFree
+		}
+	}
+
+	a.allocated++
+	return unsafe.Pointer(&a.objectPool[a.offset(index)]), nil
+}
+
+func (a *PoolAllocator) AllocateAligned(size int, align int) (unsafe.Pointer, error) {
+	if size <= 0 || size > a.objectSize {
+		return nil, errors.New("incorrect size")
+	}
+
+	if !isPowerOfTwo(align) || align > maxAlign || a.objectSize%align != 0 {
+		return nil, errors.New("incorrect alignment")
+	}
+
+	return a.allocate()
+}
+
+func (a *PoolAllocator) Deallocate(pointer unsafe.Pointer) error {
+	if pointer == nil {
+		return errors.New("incorrect pointer")
+	}
+
+	// potentionally incorrect pointer (checked only in checked mode)
+	distance := uintptr(pointer) - uintptr(unsafe.Pointer(unsafe.SliceData(a.objectPool)))
+	index := uint32(distance / uintptr(a.objectSize))
+
+	if a.checked {
+		if distance >= uintptr(len(a.objectPool)) {
+			return ErrForeignPointer
+		} else if distance%uintptr(a.objectSize) != 0 {
+			return ErrMisalignedPointer
+		} else if a.freeObjects[index] {
+			return ErrDoubleFree
+		}
+
+		a.freeObjects[index] = true
+		a.poisonObject(index)
+	}
+
+	a.setNext(index, a.head)
+	a.head = index
+	a.allocated--
+	return nil
+}
+
+func (a *PoolAllocator) Reset() {
+	a.resetMemoryState()
+}
+
+// Free is the same as Reset, there is no additional memory to release
+func (a *PoolAllocator) Free() {
+	a.Reset()
+}
+
+func (a *PoolAllocator) Stats() Stats {
+	return Stats{
+		Capacity: len(a.objectPool),
+		Used:     a.allocated * a.objectSize,
+	}
+}
+
+func (a *PoolAllocator) resetMemoryState() {
+	count := uint32(len(a.objectPool) / a.objectSize)
+	for index := uint32(0); index < count; index++ {
+		if a.checked {
+			a.freeObjects[index] = true
+			a.poisonObject(index)
+		}
+	}
+
+	for index := uint32(0); index < count-1; index++ {
+		a.setNext(index, index+1)
+	}
+
+	a.setNext(count-1, endOfList)
+	a.head = 0
+	a.allocated = 0
+}
+
+// link bytes are not poisoned, because they are used by free list
+func (a *PoolAllocator) poisonObject(index uint32) {
+	if !a.poison {
+		return
+	}
+
+	offset := a.offset(index)
+	object := a.objectPool[offset+linkWidth(a.objectSize) : offset+a.objectSize]
+	for idx := range object {
+		object[idx] = poisonPattern
+	}
+}
+
+func (a *PoolAllocator) isPoisoned(index uint32) bool {
+	if !a.poison {
+		return true
+	}
+
+	offset := a.offset(index)
+	for _, value := range a.objectPool[offset+linkWidth(a.objectSize) : offset+a.objectSize] {
+		if value != poisonPattern {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *PoolAllocator) offset(index uint32) int {
+	return int(index) * a.objectSize
+}
+
+// links can be unaligned, so they are accessed by bytes
+func (a *PoolAllocator) next(index uint32) uint32 {
+	link := a.objectPool[a.offset(index):]
+	var next uint32
+	switch linkWidth(a.objectSize) {
+	case linkSize:
+		next = binary.NativeEndian.Uint32(link)
+	case 2:
+		next = uint32(binary.NativeEndian.Uint16(link))
+	default:
+		next = uint32(link[0])
+	}
+
+	if next == linkLimit(a.objectSize) {
+		return endOfList
+	}
+
+	return next
+}
+
+// endOfList is truncated to the maximum value of narrow links
+func (a *PoolAllocator) setNext(index uint32, next uint32) {
+	link := a.objectPool[a.offset(index):]
+	switch linkWidth(a.objectSize) {
+	case linkSize:
+		binary.NativeEndian.PutUint32(link, next)
+	case 2:
+		binary.NativeEndian.PutUint16(link, uint16(next))
+	default:
+		link[0] = byte(next)
+	}
+}
@@ -0,0 +1,105 @@
+package allocators
+
+// go test -bench=. -benchmem
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+// previous version of PoolAllocator with free
+// objects stored in a map (for comparison)
+type mapPoolAllocator struct {
+	objectPool  []byte
+	freeObjects map[unsafe.Pointer]struct{}
+	objectSize  int
+}
+
+func newMapPoolAllocator(capacity int, objectSize int) mapPoolAllocator {
+	allocator := mapPoolAllocator{
+		objectPool:  make([]byte, capacity),
+		freeObjects: make(map[unsafe.Pointer]struct{}, capacity/objectSize),
+		objectSize:  objectSize,
+	}
+
+	for offset := 0; offset < len(allocator.objectPool); offset += objectSize {
+		allocator.freeObjects[unsafe.Pointer(&allocator.objectPool[offset])] = struct{}{}
+	}
+
+	return allocator
+}
+
+func (a *mapPoolAllocator) Allocate() (unsafe.Pointer, error) {
+	if len(a.freeObjects) == 0 {
+		return nil, errors.New("not enough memory")
+	}
+
+	var pointer unsafe.Pointer
+	for freePointer := range a.freeObjects {
+		pointer = freePointer
+		break
+	}
+
+	delete(a.freeObjects, pointer)
+	return pointer, nil
+}
+
+func (a *mapPoolAllocator) Deallocate(pointer unsafe.Pointer) error {
+	a.freeObjects[pointer] = struct{}{}
+	return nil
+}
+
+const (
+	benchmarkCapacity   = 1 << 16
+	benchmarkObjectSize = 64
+	benchmarkBatchSize  = 64
+)
+
+func BenchmarkIntrusivePoolAllocator(b *testing.B) {
+	allocator, _ := NewPoolAllocator(benchmarkCapacity, benchmarkObjectSize)
+	pointers := make([]unsafe.Pointer, benchmarkBatchSize)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range pointers {
+			pointers[j], _ = allocator.Allocate(allocator.objectSize)
+		}
+		for j := range pointers {
+			_ = allocator.Deallocate(pointers[j])
+		}
+	}
+}
+
+func BenchmarkMapPoolAllocator(b *testing.B) {
+	allocator := newMapPoolAllocator(benchmarkCapacity, benchmarkObjectSize)
+	pointers := make([]unsafe.Pointer, benchmarkBatchSize)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range pointers {
+			pointers[j], _ = allocator.Allocate()
+		}
+		for j := range pointers {
+			_ = allocator.Deallocate(pointers[j])
+		}
+	}
+}
+
+func BenchmarkSyncPool(b *testing.B) {
+	pool := sync.Pool{
+		New: func() interface{} { return new([benchmarkObjectSize]byte) },
+	}
+	objects := make([]*[benchmarkObjectSize]byte, benchmarkBatchSize)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range objects {
+			objects[j] = pool.Get().(*[benchmarkObjectSize]byte)
+		}
+		for j := range objects {
+			pool.Put(objects[j])
+		}
+	}
+}
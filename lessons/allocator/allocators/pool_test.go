@@ -0,0 +1,191 @@
+package allocators
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolAllocatorAlignment(t *testing.T) {
+	allocator, err := NewPoolAllocator(64, 8)
+	require.NoError(t, err)
+
+	for offset := 0; offset < len(allocator.objectPool); offset += allocator.objectSize {
+		assert.Zero(t, uintptr(unsafe.Pointer(&allocator.objectPool[offset]))%maxAlign)
+	}
+
+	_, err = allocator.AllocateAligned(16, 8)
+	assert.Error(t, err)
+	_, err = allocator.AllocateAligned(8, 16)
+	assert.Error(t, err)
+
+	value, err := New[int64](allocator)
+	require.NoError(t, err)
+	assert.Zero(t, uintptr(unsafe.Pointer(value))%unsafe.Alignof(*value))
+	assert.Zero(t, *value)
+}
+
+func TestPoolAllocatorUnsupportedAlignment(t *testing.T) {
+	allocator, err := NewPoolAllocator(36, 6)
+	require.NoError(t, err)
+
+	_, err = allocator.AllocateAligned(4, 4)
+	assert.Error(t, err)
+
+	_, err = New[int16](allocator)
+	assert.NoError(t, err)
+}
+
+func TestPoolAllocatorSmallObjects(t *testing.T) {
+	_, err := NewPoolAllocator(255, 1)
+	assert.Error(t, err)
+	_, err = NewPoolAllocator(3*65535, 3)
+	assert.Error(t, err)
+
+	for _, objectSize := range []int{1, 2, 3} {
+		allocator, err := NewCheckedPoolAllocator(254*objectSize, objectSize, true)
+		require.NoError(t, err)
+
+		pointers := make(map[unsafe.Pointer]struct{})
+		for i := 0; i < 254; i++ {
+			pointer, err := allocator.Allocate(objectSize)
+			require.NoError(t, err)
+			pointers[pointer] = struct{}{}
+		}
+
+		assert.Len(t, pointers, 254)
+		_, err = allocator.Allocate(objectSize)
+		assert.Error(t, err)
+
+		for pointer := range pointers {
+			require.NoError(t, allocator.Deallocate(pointer))
+		}
+
+		for i := 0; i < 254; i++ {
+			pointer, err := allocator.Allocate(objectSize)
+			require.NoError(t, err)
+			delete(pointers, pointer)
+		}
+
+		assert.Empty(t, pointers)
+	}
+}
+
+func TestPoolAllocatorUniqueObjects(t *testing.T) {
+	allocator, err := NewPoolAllocator(64, 4)
+	require.NoError(t, err)
+
+	pointers := make(map[unsafe.Pointer]struct{})
+	for i := 0; i < 16; i++ {
+		pointer, err := allocator.Allocate(allocator.objectSize)
+		require.NoError(t, err)
+		pointers[pointer] = struct{}{}
+	}
+
+	assert.Len(t, pointers, 16)
+	_, err = allocator.Allocate(allocator.objectSize)
+	assert.Error(t, err)
+
+	for pointer := range pointers {
+		require.NoError(t, allocator.Deallocate(pointer))
+	}
+
+	for i := 0; i < 16; i++ {
+		pointer, err := allocator.Allocate(allocator.objectSize)
+		require.NoError(t, err)
+		delete(pointers, pointer)
+	}
+
+	assert.Empty(t, pointers)
+}
+
+func TestPoolAllocatorLIFOReuse(t *testing.T) {
+	allocator, err := NewPoolAllocator(64, 8)
+	require.NoError(t, err)
+
+	pointer1, _ := allocator.Allocate(allocator.objectSize)
+	pointer2, _ := allocator.Allocate(allocator.objectSize)
+
+	require.NoError(t, allocator.Deallocate(pointer1))
+	require.NoError(t, allocator.Deallocate(pointer2))
+
+	pointer, _ := allocator.Allocate(allocator.objectSize)
+	assert.Equal(t, pointer2, pointer)
+	pointer, _ = allocator.Allocate(allocator.objectSize)
+	assert.Equal(t, pointer1, pointer)
+
+	allocator.Free()
+	pointer, _ = allocator.Allocate(allocator.objectSize)
+	assert.Equal(t, unsafe.Pointer(&allocator.objectPool[0]), pointer)
+}
+
+func TestCheckedPoolAllocatorDeallocate(t *testing.T) {
+	allocator, err := NewCheckedPoolAllocator(64, 8, false)
+	require.NoError(t, err)
+
+	// pool is placed in the middle of a bigger buffer, so pointers
+	// right outside of it are valid (checkptr rejects unsafe.Add
+	// outside of an allocation)
+	buffer := alignedBuffer(3*64, maxAlign)
+	allocator.objectPool = buffer[64:128]
+	allocator.resetMemoryState()
+
+	pointer, err := allocator.Allocate(allocator.objectSize)
+	require.NoError(t, err)
+	require.Equal(t, unsafe.Pointer(&buffer[64]), pointer)
+
+	var foreign int64
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Pointer(&foreign)), ErrForeignPointer)
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Pointer(&buffer[63])), ErrForeignPointer)
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Pointer(&buffer[128])), ErrForeignPointer)
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Add(pointer, 3)), ErrMisalignedPointer)
+
+	unused := unsafe.Add(pointer, 8)
+	assert.ErrorIs(t, allocator.Deallocate(unused), ErrDoubleFree)
+
+	assert.NoError(t, allocator.Deallocate(pointer))
+	assert.ErrorIs(t, allocator.Deallocate(pointer), ErrDoubleFree)
+
+	again, err := allocator.Allocate(allocator.objectSize)
+	require.NoError(t, err)
+	assert.Equal(t, pointer, again)
+}
+
+func TestCheckedPoolAllocatorPoison(t *testing.T) {
+	allocator, err := NewCheckedPoolAllocator(64, 16, true)
+	require.NoError(t, err)
+
+	pointer, err := allocator.Allocate(allocator.objectSize)
+	require.NoError(t, err)
+	Store[int64](unsafe.Add(pointer, 8), 100)
+
+	require.NoError(t, allocator.Deallocate(pointer))
+	assert.Equal(t, byte(poisonPattern), Load[byte](unsafe.Add(pointer, 8)))
+
+	// use after free
+	Store[int64](unsafe.Add(pointer, 8), 200)
+
+	_, err = allocator.Allocate(allocator.objectSize)
+	assert.ErrorIs(t, err, ErrUseAfterFree)
+
+	allocator.Free()
+	_, err = allocator.Allocate(allocator.objectSize)
+	assert.NoError(t, err)
+}
+
+func TestCheckedPoolAllocatorCorruptedLink(t *testing.T) {
+	allocator, err := NewCheckedPoolAllocator(64, 8, false)
+	require.NoError(t, err)
+
+	pointer, err := allocator.Allocate(allocator.objectSize)
+	require.NoError(t, err)
+	require.NoError(t, allocator.Deallocate(pointer))
+
+	// use after free
+	Store[uint32](pointer, 1000)
+
+	_, err = allocator.Allocate(allocator.objectSize)
+	assert.ErrorIs(t, err, ErrUseAfterFree)
+}
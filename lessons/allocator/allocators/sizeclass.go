@@ -0,0 +1,252 @@
+package allocators
+
+import (
+	"errors"
+	"sort"
+	"unsafe"
+)
+
+const (
+	pageSize     = 8192
+	maxSmallSize = 32768
+)
+
+// size classes and span sizes (in pages) of the Go runtime,
+// see internal/runtime/gc/sizeclasses.go (without class 0)
+var (
+	classSizes = [...]int{
+		8, 16, 24, 32, 48, 64, 80, 96, 112, 128, 144, 160, 176, 192, 208, 224, 240, 256,
+		288, 320, 352, 384, 416, 448, 480, 512, 576, 640, 704, 768, 896, 1024, 1152, 1280,
+		1408, 1536, 1792, 2048, 2304, 2688, 3072, 3200, 3456, 4096, 4864, 5376, 6144, 6528,
+		6784, 6912, 8192, 9472, 9728, 10240, 10880, 12288, 13568, 14336, 16384, 18432, 19072,
+		20480, 21760, 24576, 27264, 28672, 32768,
+	}
+	classPages = [...]int{
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		2, 1, 2, 1, 2, 1, 3, 2, 3, 1, 3, 2, 3, 4,
+		5, 6, 1, 7, 6, 5, 4, 3, 5, 7, 2, 9, 7,
+		5, 8, 3, 10, 7, 4,
+	}
+)
+
+// classSpan is a PoolAllocator over several pages with objects of one size
+// class, the tail of pages that doesn't fit an object is wasted like in the runtime
+type classSpan struct {
+	pool *PoolAllocator
+	base uintptr
+	size int // in bytes including the tail
+}
+
+type sizeClass struct {
+	spans   []*classSpan
+	current int
+}
+
+// SizeClassAllocator rounds requests up to size classes and serves each class
+// from its own spans (like mcache/mcentral), requests bigger than 32 KB are
+// served by whole pages (like mheap)
+type SizeClassAllocator struct {
+	classes [len(classSizes)]sizeClass
+
+	// spans sorted by address to find span of a pointer
+	spans []*classSpan
+
+	// large allocations by address
+	large     map[uintptr][]byte
+	largeUsed int
+}
+
+type ClassStats struct {
+	Size      int // object size
+	Pages     int // pages per span
+	Spans     int
+	Objects   int // live objects
+	TailWaste int // bytes per span that don't fit an object
+}
+
+func NewSizeClassAllocator() *SizeClassAllocator {
+	return &SizeClassAllocator{
+		large: make(map[uintptr][]byte),
+	}
+}
+
+func (a *SizeClassAllocator) Allocate(size int) (unsafe.Pointer, error) {
+	return a.AllocateAligned(size, 1)
+}
+
+// AllocateAligned chooses the first class that fits size and has object size
+// multiple of align, because spans start at page boundaries
+func (a *SizeClassAllocator) AllocateAligned(size int, align int) (unsafe.Pointer, error) {
+	if size <= 0 {
+		return nil, errors.New("incorrect size")
+	}
+
+	if !isPowerOfTwo(align) || align > pageSize {
+		return nil, errors.New("incorrect alignment")
+	}
+
+	if size > maxSmallSize {
+		return a.allocateLarge(size), nil
+	}
+
+	class := sort.SearchInts(classSizes[:], size)
+	for class < len(classSizes) && classSizes[class]%align != 0 {
+		class++
+	}
+
+	if class == len(classSizes) {
+		return a.allocateLarge(size), nil
+	}
+
+	return a.availableSpan(class).pool.allocate()
+}
+
+func (a *SizeClassAllocator) Deallocate(pointer unsafe.Pointer) error {
+	if pointer == nil {
+		return errors.New("incorrect pointer")
+	}
+
+	address := uintptr(pointer)
+	idx := sort.Search(len(a.spans), func(idx int) bool {
+		return a.spans[idx].base > address
+	}) - 1
+
+	if idx >= 0 && address < a.spans[idx].base+uintptr(a.spans[idx].size) {
+		span := a.spans[idx]
+		distance := address - span.base
+		if distance >= uintptr(len(span.pool.objectPool)) {
+			// tail of the span
+			return ErrForeignPointer
+		} else if distance%uintptr(span.pool.objectSize) != 0 {
+			return ErrMisalignedPointer
+		}
+
+		return span.pool.Deallocate(pointer)
+	}
+
+	if memory, found := a.large[address]; found {
+		delete(a.large, address)
+		a.largeUsed -= len(memory)
+		return nil
+	}
+
+	return ErrForeignPointer
+}
+
+// Reset keeps spans, but releases large allocations
+func (a *SizeClassAllocator) Reset() {
+	for _, span := range a.spans {
+		span.pool.Reset()
+	}
+
+	for class := range a.classes {
+		a.classes[class].current = 0
+	}
+
+	clear(a.large)
+	a.largeUsed = 0
+}
+
+// Free releases all spans and large allocations
+func (a *SizeClassAllocator) Free() {
+	a.classes = [len(classSizes)]sizeClass{}
+	a.spans = nil
+	clear(a.large)
+	a.largeUsed = 0
+}
+
+func (a *SizeClassAllocator) Stats() Stats {
+	stats := Stats{
+		Capacity: a.largeUsed,
+		Used:     a.largeUsed,
+	}
+
+	for _, span := range a.spans {
+		stats.Capacity += span.size
+		stats.Used += span.pool.Stats().Used
+	}
+
+	return stats
+}
+
+// ClassStats returns statistics of classes with at least one span
+func (a *SizeClassAllocator) ClassStats() []ClassStats {
+	var stats []ClassStats
+	for class, sizeClass := range a.classes {
+		if len(sizeClass.spans) == 0 {
+			continue
+		}
+
+		classStats := ClassStats{
+			Size:      classSizes[class],
+			Pages:     classPages[class],
+			Spans:     len(sizeClass.spans),
+			TailWaste: classPages[class] * pageSize % classSizes[class],
+		}
+
+		for _, span := range sizeClass.spans {
+			classStats.Objects += span.pool.allocated
+		}
+
+		stats = append(stats, classStats)
+	}
+
+	return stats
+}
+
+// availableSpan starts search from the span of the last
+// allocation and adds a new span if all spans are full
+func (a *SizeClassAllocator) availableSpan(class int) *classSpan {
+	sizeClass := &a.classes[class]
+	for idx := 0; idx < len(sizeClass.spans); idx++ {
+		spanIdx := (sizeClass.current + idx) % len(sizeClass.spans)
+		if sizeClass.spans[spanIdx].pool.head != endOfList {
+			sizeClass.current = spanIdx
+			return sizeClass.spans[spanIdx]
+		}
+	}
+
+	span := a.newSpan(class)
+	sizeClass.spans = append(sizeClass.spans, span)
+	sizeClass.current = len(sizeClass.spans) - 1
+	return span
+}
+
+func (a *SizeClassAllocator) newSpan(class int) *classSpan {
+	size := classPages[class] * pageSize
+	objectSize := classSizes[class]
+
+	memory := alignedBuffer(size, pageSize)
+	pool := &PoolAllocator{
+		objectPool: memory[:size/objectSize*objectSize],
+		objectSize: objectSize,
+	}
+
+	pool.resetMemoryState()
+
+	span := &classSpan{
+		pool: pool,
+		base: uintptr(unsafe.Pointer(unsafe.SliceData(memory))),
+		size: size,
+	}
+
+	idx := sort.Search(len(a.spans), func(idx int) bool {
+		return a.spans[idx].base > span.base
+	})
+
+	a.spans = append(a.spans, nil)
+	copy(a.spans[idx+1:], a.spans[idx:])
+	a.spans[idx] = span
+	return span
+}
+
+func (a *SizeClassAllocator) allocateLarge(size int) unsafe.Pointer {
+	pages := (size + pageSize - 1) / pageSize
+	memory := alignedBuffer(pages*pageSize, pageSize)
+	pointer := unsafe.Pointer(unsafe.SliceData(memory))
+
+	a.large[uintptr(pointer)] = memory
+	a.largeUsed += len(memory)
+	return pointer
+}
@@ -0,0 +1,96 @@
+package allocators
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSizeClassTables(t *testing.T) {
+	require.Equal(t, len(classSizes), len(classPages))
+	for class := 1; class < len(classSizes); class++ {
+		assert.Less(t, classSizes[class-1], classSizes[class])
+		assert.LessOrEqual(t, classSizes[class], classPages[class]*pageSize)
+	}
+}
+
+func TestSizeClassAllocatorRounding(t *testing.T) {
+	allocator := NewSizeClassAllocator()
+	tracked := NewStatsAllocator(allocator)
+
+	// the same as string(buffer) with 33 bytes
+	_, err := tracked.Allocate(33)
+	require.NoError(t, err)
+	_, err = tracked.Allocate(33)
+	require.NoError(t, err)
+	_, err = tracked.Allocate(66)
+	require.NoError(t, err)
+
+	stats := tracked.Stats()
+	assert.Equal(t, 132, stats.RequestedBytes)
+	assert.Equal(t, 48+48+80, stats.ConsumedBytes)
+	assert.Equal(t, 2*pageSize, stats.Capacity)
+
+	assert.Equal(t, []ClassStats{
+		{Size: 48, Pages: 1, Spans: 1, Objects: 2, TailWaste: 32},
+		{Size: 80, Pages: 1, Spans: 1, Objects: 1, TailWaste: 32},
+	}, allocator.ClassStats())
+}
+
+func TestSizeClassAllocatorSpans(t *testing.T) {
+	allocator := NewSizeClassAllocator()
+
+	// 8192 / 48 = 170 objects in one span
+	pointers := make([]unsafe.Pointer, 0, 171)
+	for i := 0; i < 171; i++ {
+		pointer, err := allocator.Allocate(48)
+		require.NoError(t, err)
+		pointers = append(pointers, pointer)
+	}
+
+	assert.Equal(t, 2, allocator.ClassStats()[0].Spans)
+
+	for _, pointer := range pointers {
+		require.NoError(t, allocator.Deallocate(pointer))
+	}
+
+	assert.Equal(t, 0, allocator.Stats().Used)
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Add(pointers[0], 1)), ErrMisalignedPointer)
+
+	// tail of the first span
+	tail := unsafe.Add(pointers[0], 170*48)
+	assert.ErrorIs(t, allocator.Deallocate(tail), ErrForeignPointer)
+
+	var foreign int64
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Pointer(&foreign)), ErrForeignPointer)
+}
+
+func TestSizeClassAllocatorLarge(t *testing.T) {
+	allocator := NewSizeClassAllocator()
+
+	pointer, err := allocator.Allocate(maxSmallSize + 1)
+	require.NoError(t, err)
+	assert.Zero(t, uintptr(pointer)%pageSize)
+	assert.Equal(t, Stats{Capacity: 5 * pageSize, Used: 5 * pageSize}, allocator.Stats())
+	assert.Empty(t, allocator.ClassStats())
+
+	require.NoError(t, allocator.Deallocate(pointer))
+	assert.ErrorIs(t, allocator.Deallocate(pointer), ErrForeignPointer)
+	assert.Equal(t, Stats{}, allocator.Stats())
+}
+
+func TestSizeClassAllocatorAlignment(t *testing.T) {
+	allocator := NewSizeClassAllocator()
+
+	// 48 is not multiple of 32, so class 64 is used
+	pointer, err := allocator.AllocateAligned(40, 32)
+	require.NoError(t, err)
+	assert.Zero(t, uintptr(pointer)%32)
+	assert.Equal(t, 64, allocator.ClassStats()[0].Size)
+
+	pointer, err = allocator.AllocateAligned(9000, 8192)
+	require.NoError(t, err)
+	assert.Zero(t, uintptr(pointer)%8192)
+}
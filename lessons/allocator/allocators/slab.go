@@ -0,0 +1,159 @@
+package allocators
+
+import (
+	"errors"
+	"reflect"
+	"unsafe"
+)
+
+// ErrPointerType is returned for types with Go pointers,
+// because GC doesn't scan memory of custom allocators
+var ErrPointerType = errors.New("type contains pointers")
+
+// Slab allocates values of type T from pages of checked PoolAllocator
+// (without poisoning) and adds new pages on demand, so incorrect and
+// repeated deletions are reported instead of corrupting the free list
+type Slab[T any] struct {
+	pages          []*PoolAllocator
+	current        int
+	objectSize     int
+	objectsPerPage int
+}
+
+func NewSlab[T any](objectsPerPage int) (*Slab[T], error) {
+	if objectsPerPage <= 0 {
+		return nil, errors.New("incorrect objects per page")
+	}
+
+	valueType := reflect.TypeFor[T]()
+	if HasPointers(valueType) {
+		return nil, ErrPointerType
+	}
+
+	align := valueType.Align()
+	if align > maxAlign {
+		return nil, errors.New("unsupported alignment")
+	}
+
+	// free object must have space for link of the free list
+	size := max(int(valueType.Size()), linkSize)
+	objectSize := (size + align - 1) &^ (align - 1)
+
+	slab := &Slab[T]{
+		objectSize:     objectSize,
+		objectsPerPage: objectsPerPage,
+	}
+
+	if err := slab.addPage(); err != nil {
+		return nil, err
+	}
+
+	return slab, nil
+}
+
+// New returns zeroed value, it is valid until Delete or Reset
+func (s *Slab[T]) New() (*T, error) {
+	page, err := s.availablePage()
+	if err != nil {
+		return nil, err
+	}
+
+	pointer, err := page.allocate()
+	if err != nil {
+		return nil, err
+	}
+
+	value := (*T)(pointer)
+	*value = *new(T)
+	return value, nil
+}
+
+func (s *Slab[T]) Delete(value *T) error {
+	if value == nil {
+		return errors.New("incorrect pointer")
+	}
+
+	pointer := unsafe.Pointer(value)
+	for idx, page := range s.pages {
+		distance := uintptr(pointer) - uintptr(unsafe.Pointer(unsafe.SliceData(page.objectPool)))
+		if distance < uintptr(len(page.objectPool)) {
+			s.current = idx
+			return page.Deallocate(pointer)
+		}
+	}
+
+	return ErrForeignPointer
+}
+
+func (s *Slab[T]) Reset() {
+	for _, page := range s.pages {
+		page.Reset()
+	}
+
+	s.current = 0
+}
+
+// Free releases all pages except the first one
+func (s *Slab[T]) Free() {
+	s.pages = s.pages[:1]
+	s.Reset()
+}
+
+func (s *Slab[T]) Stats() Stats {
+	var stats Stats
+	for _, page := range s.pages {
+		pageStats := page.Stats()
+		stats.Capacity += pageStats.Capacity
+		stats.Used += pageStats.Used
+	}
+
+	return stats
+}
+
+// availablePage starts search from the page of the last
+// deallocation and adds a new page if all pages are full
+func (s *Slab[T]) availablePage() (*PoolAllocator, error) {
+	for idx := 0; idx < len(s.pages); idx++ {
+		pageIdx := (s.current + idx) % len(s.pages)
+		if s.pages[pageIdx].head != endOfList {
+			s.current = pageIdx
+			return s.pages[pageIdx], nil
+		}
+	}
+
+	if err := s.addPage(); err != nil {
+		return nil, err
+	}
+
+	s.current = len(s.pages) - 1
+	return s.pages[s.current], nil
+}
+
+func (s *Slab[T]) addPage() error {
+	page, err := NewCheckedPoolAllocator(s.objectsPerPage*s.objectSize, s.objectSize, false)
+	if err != nil {
+		return err
+	}
+
+	s.pages = append(s.pages, page)
+	return nil
+}
+
+// HasPointers reports whether values of the type contain Go pointers
+func HasPointers(valueType reflect.Type) bool {
+	switch valueType.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Map, reflect.Slice,
+		reflect.String, reflect.Chan, reflect.Func, reflect.Interface:
+		return true
+	case reflect.Array:
+		return valueType.Len() != 0 && HasPointers(valueType.Elem())
+	case reflect.Struct:
+		for idx := 0; idx < valueType.NumField(); idx++ {
+			if HasPointers(valueType.Field(idx).Type) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
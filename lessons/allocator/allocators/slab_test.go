@@ -0,0 +1,90 @@
+package allocators
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type point struct {
+	x int64
+	y int32
+	z bool
+}
+
+func TestSlabPointerTypes(t *testing.T) {
+	_, err := NewSlab[*int](16)
+	assert.ErrorIs(t, err, ErrPointerType)
+	_, err = NewSlab[string](16)
+	assert.ErrorIs(t, err, ErrPointerType)
+	_, err = NewSlab[struct{ values []int }](16)
+	assert.ErrorIs(t, err, ErrPointerType)
+	_, err = NewSlab[[4]map[int]int](16)
+	assert.ErrorIs(t, err, ErrPointerType)
+
+	_, err = NewSlab[[0]*int](16)
+	assert.NoError(t, err)
+	_, err = NewSlab[struct{}](16)
+	assert.NoError(t, err)
+}
+
+func TestSlabGrowth(t *testing.T) {
+	slab, err := NewSlab[point](4)
+	require.NoError(t, err)
+	assert.Equal(t, 16, slab.objectSize)
+
+	values := make([]*point, 0, 10)
+	for i := 0; i < 10; i++ {
+		value, err := slab.New()
+		require.NoError(t, err)
+		assert.Zero(t, uintptr(unsafe.Pointer(value))%unsafe.Alignof(*value))
+
+		*value = point{x: int64(i), y: int32(i), z: true}
+		values = append(values, value)
+	}
+
+	assert.Len(t, slab.pages, 3)
+	for i, value := range values {
+		assert.Equal(t, point{x: int64(i), y: int32(i), z: true}, *value)
+	}
+
+	require.NoError(t, slab.Delete(values[1]))
+	assert.ErrorIs(t, slab.Delete(&point{}), ErrForeignPointer)
+
+	value, err := slab.New()
+	require.NoError(t, err)
+	assert.Equal(t, values[1], value)
+	assert.Equal(t, point{}, *value)
+	assert.Len(t, slab.pages, 3)
+
+	slab.Free()
+	assert.Len(t, slab.pages, 1)
+	assert.Equal(t, Stats{Capacity: 64}, slab.Stats())
+}
+
+func TestSlabDoubleDelete(t *testing.T) {
+	slab, err := NewSlab[point](4)
+	require.NoError(t, err)
+
+	first, err := slab.New()
+	require.NoError(t, err)
+	second, err := slab.New()
+	require.NoError(t, err)
+
+	require.NoError(t, slab.Delete(first))
+	assert.ErrorIs(t, slab.Delete(first), ErrDoubleFree)
+	assert.ErrorIs(t, slab.Delete((*point)(unsafe.Add(unsafe.Pointer(second), 8))), ErrMisalignedPointer)
+
+	// free list isn't corrupted, so the object is returned once
+	value, err := slab.New()
+	require.NoError(t, err)
+	assert.Same(t, first, value)
+
+	value, err = slab.New()
+	require.NoError(t, err)
+	assert.NotSame(t, first, value)
+	assert.NotSame(t, second, value)
+	assert.Equal(t, Stats{Capacity: 64, Used: 48}, slab.Stats())
+}
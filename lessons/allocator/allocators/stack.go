@@ -0,0 +1,198 @@
+package allocators
+
+import (
+	"encoding/binary"
+	"errors"
+	"unsafe"
+)
+
+var (
+	ErrOutOfOrder      = errors.New("pointer is not the last allocation")
+	ErrIncorrectMarker = errors.New("incorrect marker")
+)
+
+// Marker is a position of the stack top, all allocations
+// made after it can be released by RollbackTo
+type Marker int
+
+type StackAllocator struct {
+	data []byte
+
+	// data offset of the last allocation (0 if there are no
+	// allocations, data always follows at least one header byte)
+	top int
+}
+
+func NewStackAllocator(capacity int) (*StackAllocator, error) {
+	if capacity <= 0 {
+		return nil, errors.New("incorrect capacity")
+	}
+
+	return &StackAllocator{
+		data: make([]byte, 0, capacity),
+	}, nil
+}
+
+func (a *StackAllocator) Allocate(size int) (unsafe.Pointer, error) {
+	return a.AllocateAligned(size, 1)
+}
+
+// AllocateAligned places padding before the header, so memory
+// layout of each allocation is [padding][header][data]
+//
+// Header contains size of data, distance from the previous
+// stack top to data and distance from data of the previous
+// allocation to data, all of them are variable-width and
+// written in reverse order, so they can be read back from
+// the pointer. The last one restores the top on deallocation,
+// so only the exact pointer of the top allocation is accepted
+// (bytes before an interior pointer are data, not a header).
+func (a *StackAllocator) AllocateAligned(size int, align int) (unsafe.Pointer, error) {
+	if size <= 0 {
+		return nil, errors.New("incorrect size")
+	}
+
+	if !isPowerOfTwo(align) {
+		return nil, errors.New("incorrect alignment")
+	}
+
+	previousLength := len(a.data)
+	headerSize := uvarintSize(uint64(size)) + 2
+
+	var dataOffset, distance int
+	for {
+		dataOffset = alignUp(a.data, previousLength+headerSize, align)
+		distance = dataOffset - previousLength
+
+		// distances can require more bytes because of padding
+		requiredSize := uvarintSize(uint64(size)) + uvarintSize(uint64(distance)) + uvarintSize(uint64(dataOffset-a.top))
+		if requiredSize <= headerSize {
+			break
+		}
+
+		headerSize = requiredSize
+	}
+
+	newLength := dataOffset + size
+	if newLength > cap(a.data) {
+		// can increase capacity
+		return nil, errors.New("not enough memory")
+	}
+
+	a.data = a.data[:newLength]
+	headerEnd := putReversedUvarint(a.data, dataOffset, uint64(size))
+	headerEnd = putReversedUvarint(a.data, headerEnd, uint64(distance))
+	putReversedUvarint(a.data, headerEnd, uint64(dataOffset-a.top))
+	a.top = dataOffset
+
+	pointer := unsafe.Pointer(&a.data[dataOffset])
+	return pointer, nil
+}
+
+// Deallocate releases only the last allocation
+func (a *StackAllocator) Deallocate(pointer unsafe.Pointer) error {
+	if pointer == nil {
+		return errors.New("incorrect pointer")
+	}
+
+	distanceFromData := uintptr(pointer) - uintptr(unsafe.Pointer(unsafe.SliceData(a.data)))
+	if distanceFromData == 0 || distanceFromData >= uintptr(len(a.data)) {
+		return errors.New("incorrect pointer")
+	}
+
+	dataOffset := int(distanceFromData)
+	if dataOffset != a.top {
+		return ErrOutOfOrder
+	}
+
+	_, headerEnd := reversedUvarint(a.data, dataOffset)
+	distance, headerEnd := reversedUvarint(a.data, headerEnd)
+	topDistance, _ := reversedUvarint(a.data, headerEnd)
+
+	previousLength := dataOffset - int(distance)
+	previousTop := dataOffset - int(topDistance)
+	if distance > uint64(dataOffset) || topDistance > uint64(dataOffset) || previousTop > previousLength {
+		return errors.New("corrupted header")
+	}
+
+	a.data = a.data[:previousLength]
+	a.top = previousTop
+	return nil
+}
+
+func (a *StackAllocator) Marker() Marker {
+	return Marker(len(a.data))
+}
+
+// RollbackTo releases all allocations made after the marker
+func (a *StackAllocator) RollbackTo(marker Marker) error {
+	if marker < 0 || int(marker) > len(a.data) {
+		return ErrIncorrectMarker
+	}
+
+	// allocations that end after the marker are released
+	for a.top != 0 {
+		size, headerEnd := reversedUvarint(a.data, a.top)
+		if a.top+int(size) <= int(marker) {
+			break
+		}
+
+		_, headerEnd = reversedUvarint(a.data, headerEnd)
+		topDistance, _ := reversedUvarint(a.data, headerEnd)
+		a.top -= int(topDistance)
+	}
+
+	a.data = a.data[:marker]
+	return nil
+}
+
+func (a *StackAllocator) Reset() {
+	a.data = a.data[:0]
+	a.top = 0
+}
+
+// Free is the same as Reset, there is no additional memory to release
+func (a *StackAllocator) Free() {
+	a.Reset()
+}
+
+func (a *StackAllocator) Stats() Stats {
+	return Stats{
+		Capacity: cap(a.data),
+		Used:     len(a.data),
+	}
+}
+
+func uvarintSize(value uint64) int {
+	var buffer [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buffer[:], value)
+}
+
+// putReversedUvarint writes value before data[end] with
+// reversed byte order and returns offset of the first byte
+func putReversedUvarint(data []byte, end int, value uint64) int {
+	var buffer [binary.MaxVarintLen64]byte
+	size := binary.PutUvarint(buffer[:], value)
+	for idx := 0; idx < size; idx++ {
+		data[end-1-idx] = buffer[idx]
+	}
+
+	return end - size
+}
+
+// reversedUvarint reads value written by putReversedUvarint
+// and returns it with offset of the first byte
+func reversedUvarint(data []byte, end int) (uint64, int) {
+	var value uint64
+	var shift uint
+	for offset := end - 1; offset >= 0; offset-- {
+		value |= uint64(data[offset]&0x7F) << shift
+		if data[offset] < 0x80 {
+			return value, offset
+		}
+
+		shift += 7
+	}
+
+	return value, 0
+}
@@ -0,0 +1,134 @@
+package allocators
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStackAllocatorAlignment(t *testing.T) {
+	allocator, err := NewStackAllocator(128)
+	require.NoError(t, err)
+
+	_, err = allocator.AllocateAligned(4, 6)
+	assert.Error(t, err)
+
+	pointer1, err := allocator.Allocate(1)
+	require.NoError(t, err)
+	length1 := len(allocator.data)
+
+	pointer2, err := allocator.AllocateAligned(4, 4)
+	require.NoError(t, err)
+	assert.Zero(t, uintptr(pointer2)%4)
+	length2 := len(allocator.data)
+
+	value, err := New[int64](allocator)
+	require.NoError(t, err)
+	assert.Zero(t, uintptr(unsafe.Pointer(value))%unsafe.Alignof(*value))
+	assert.Zero(t, *value)
+
+	require.NoError(t, allocator.Deallocate(unsafe.Pointer(value)))
+	assert.Equal(t, length2, len(allocator.data))
+	require.NoError(t, allocator.Deallocate(pointer2))
+	assert.Equal(t, length1, len(allocator.data))
+	require.NoError(t, allocator.Deallocate(pointer1))
+	assert.Equal(t, 0, len(allocator.data))
+}
+
+func TestStackAllocatorOutOfMemory(t *testing.T) {
+	allocator, err := NewStackAllocator(16)
+	require.NoError(t, err)
+
+	_, err = allocator.Allocate(0)
+	assert.Error(t, err)
+
+	// header of small allocation takes 3 bytes
+	_, err = allocator.Allocate(13)
+	assert.NoError(t, err)
+	_, err = allocator.Allocate(1)
+	assert.Error(t, err)
+}
+
+func TestStackAllocatorOutOfOrder(t *testing.T) {
+	allocator, err := NewStackAllocator(128)
+	require.NoError(t, err)
+
+	pointer1, err := allocator.Allocate(8)
+	require.NoError(t, err)
+	pointer2, err := allocator.Allocate(8)
+	require.NoError(t, err)
+
+	length := len(allocator.data)
+	assert.ErrorIs(t, allocator.Deallocate(pointer1), ErrOutOfOrder)
+	assert.Equal(t, length, len(allocator.data))
+
+	var foreign int64
+	assert.Error(t, allocator.Deallocate(unsafe.Pointer(&foreign)))
+
+	assert.NoError(t, allocator.Deallocate(pointer2))
+	assert.NoError(t, allocator.Deallocate(pointer1))
+	assert.Equal(t, 0, len(allocator.data))
+}
+
+func TestStackAllocatorInteriorPointer(t *testing.T) {
+	allocator, err := NewStackAllocator(128)
+	require.NoError(t, err)
+
+	pointer, err := allocator.Allocate(8)
+	require.NoError(t, err)
+
+	// data before the interior pointer looks like a valid header
+	data := unsafe.Slice((*byte)(pointer), 8)
+	data[5] = 0x7f
+	data[6] = 1
+
+	length := len(allocator.data)
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Add(pointer, 7)), ErrOutOfOrder)
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Add(pointer, 1)), ErrOutOfOrder)
+	assert.Equal(t, length, len(allocator.data))
+
+	assert.NoError(t, allocator.Deallocate(pointer))
+	assert.Equal(t, 0, len(allocator.data))
+}
+
+func TestStackAllocatorMarker(t *testing.T) {
+	allocator, err := NewStackAllocator(256)
+	require.NoError(t, err)
+
+	pointer, err := allocator.Allocate(16)
+	require.NoError(t, err)
+
+	marker := allocator.Marker()
+	for i := 0; i < 5; i++ {
+		_, err = New[int64](allocator)
+		require.NoError(t, err)
+	}
+
+	assert.ErrorIs(t, allocator.RollbackTo(Marker(1000)), ErrIncorrectMarker)
+	assert.ErrorIs(t, allocator.RollbackTo(Marker(-1)), ErrIncorrectMarker)
+
+	require.NoError(t, allocator.RollbackTo(marker))
+	assert.Equal(t, int(marker), len(allocator.data))
+	assert.ErrorIs(t, allocator.Deallocate(unsafe.Add(pointer, 8)), ErrOutOfOrder)
+	assert.NoError(t, allocator.Deallocate(pointer))
+	assert.Equal(t, 0, len(allocator.data))
+}
+
+func TestStackAllocatorLargeAllocation(t *testing.T) {
+	const size = 1 << 20
+	allocator, err := NewStackAllocator(4 * size)
+	require.NoError(t, err)
+
+	pointer1, err := allocator.Allocate(size)
+	require.NoError(t, err)
+
+	pointer2, err := allocator.AllocateAligned(size, 4096)
+	require.NoError(t, err)
+	assert.Zero(t, uintptr(pointer2)%4096)
+
+	require.NoError(t, allocator.Deallocate(pointer2))
+	require.NoError(t, allocator.Deallocate(pointer1))
+	assert.Equal(t, 0, len(allocator.data))
+}
@@ -0,0 +1,96 @@
+package allocators
+
+import (
+	"fmt"
+	"io"
+	"unsafe"
+)
+
+// StatsAllocator records usage counters of the wrapped allocator
+type StatsAllocator struct {
+	allocator Allocator
+	stats     Stats
+}
+
+func NewStatsAllocator(allocator Allocator) *StatsAllocator {
+	return &StatsAllocator{allocator: allocator}
+}
+
+func (a *StatsAllocator) Allocate(size int) (unsafe.Pointer, error) {
+	return a.AllocateAligned(size, 1)
+}
+
+func (a *StatsAllocator) AllocateAligned(size int, align int) (unsafe.Pointer, error) {
+	previousUsed := a.allocator.Stats().Used
+	pointer, err := a.allocator.AllocateAligned(size, align)
+	if err != nil {
+		a.stats.FailedAllocations++
+		return nil, err
+	}
+
+	used := a.allocator.Stats().Used
+	a.stats.Allocations++
+	a.stats.LiveAllocations++
+	a.stats.PeakUsed = max(a.stats.PeakUsed, used)
+	a.stats.RequestedBytes += size
+	a.stats.ConsumedBytes += used - previousUsed
+	return pointer, nil
+}
+
+func (a *StatsAllocator) Deallocate(pointer unsafe.Pointer) error {
+	if err := a.allocator.Deallocate(pointer); err != nil {
+		return err
+	}
+
+	a.stats.Deallocations++
+	a.stats.LiveAllocations--
+	return nil
+}
+
+func (a *StatsAllocator) Reset() {
+	a.allocator.Reset()
+	a.stats.LiveAllocations = 0
+}
+
+// Stats returns snapshot of counters, they are
+// accumulated since creation and not cleared by Reset
+func (a *StatsAllocator) Stats() Stats {
+	stats := a.stats
+	current := a.allocator.Stats()
+	stats.Capacity = current.Capacity
+	stats.Used = current.Used
+	return stats
+}
+
+// WriteReport prints usage of allocator memory: internal waste is
+// memory consumed by padding, headers and rounding to object size,
+// unused memory is capacity that was never needed at peak
+func WriteReport(w io.Writer, stats Stats) error {
+	internalWaste := stats.ConsumedBytes - stats.RequestedBytes
+	unused := stats.Capacity - stats.PeakUsed
+
+	_, err := fmt.Fprintf(w,
+		"capacity: %d\n"+
+			"used: %d (peak: %d)\n"+
+			"allocations: %d (live: %d, deallocations: %d, failed: %d)\n"+
+			"requested: %d, consumed: %d\n"+
+			"internal waste: %d (%.1f%%)\n"+
+			"unused at peak: %d (%.1f%%)\n",
+		stats.Capacity,
+		stats.Used, stats.PeakUsed,
+		stats.Allocations, stats.LiveAllocations, stats.Deallocations, stats.FailedAllocations,
+		stats.RequestedBytes, stats.ConsumedBytes,
+		internalWaste, percent(internalWaste, stats.ConsumedBytes),
+		unused, percent(unused, stats.Capacity),
+	)
+
+	return err
+}
+
+func percent(value int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(value) * 100 / float64(total)
+}
@@ -0,0 +1,75 @@
+package allocators
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsAllocator(t *testing.T) {
+	pool, err := NewPoolAllocator(64, 16)
+	require.NoError(t, err)
+	allocator := NewStatsAllocator(pool)
+
+	pointer1, err := allocator.Allocate(10)
+	require.NoError(t, err)
+	_, err = allocator.Allocate(16)
+	require.NoError(t, err)
+	_, err = allocator.Allocate(32)
+	assert.Error(t, err)
+
+	require.NoError(t, allocator.Deallocate(pointer1))
+	assert.Error(t, allocator.Deallocate(nil))
+
+	assert.Equal(t, Stats{
+		Capacity:          64,
+		Used:              16,
+		Allocations:       2,
+		Deallocations:     1,
+		FailedAllocations: 1,
+		LiveAllocations:   1,
+		PeakUsed:          32,
+		RequestedBytes:    26,
+		ConsumedBytes:     32,
+	}, allocator.Stats())
+
+	allocator.Reset()
+	stats := allocator.Stats()
+	assert.Equal(t, 0, stats.LiveAllocations)
+	assert.Equal(t, 0, stats.Used)
+	assert.Equal(t, 32, stats.PeakUsed)
+}
+
+func TestStatsAllocatorPadding(t *testing.T) {
+	stack, err := NewStackAllocator(1024)
+	require.NoError(t, err)
+	allocator := NewStatsAllocator(stack)
+
+	_, err = allocator.Allocate(1)
+	require.NoError(t, err)
+	_, err = New[int64](allocator)
+	require.NoError(t, err)
+
+	stats := allocator.Stats()
+	assert.Equal(t, 9, stats.RequestedBytes)
+	assert.Equal(t, stats.Used, stats.ConsumedBytes)
+	assert.Greater(t, stats.ConsumedBytes, stats.RequestedBytes)
+}
+
+func TestWriteReport(t *testing.T) {
+	var builder strings.Builder
+	err := WriteReport(&builder, Stats{
+		Capacity:       100,
+		Used:           40,
+		PeakUsed:       50,
+		Allocations:    5,
+		RequestedBytes: 40,
+		ConsumedBytes:  50,
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, builder.String(), "internal waste: 10 (20.0%)")
+	assert.Contains(t, builder.String(), "unused at peak: 50 (50.0%)")
+}
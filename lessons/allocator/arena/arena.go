@@ -0,0 +1,5 @@
+// Package arena mirrors API of the experimental arena package: with
+// GOEXPERIMENT=arenas it uses the runtime arenas, otherwise values without
+// pointers are placed into chunks of the linear allocator and other values
+// are allocated on the heap, so the same code compiles in both cases
+package arena
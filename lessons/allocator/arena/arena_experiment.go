@@ -0,0 +1,31 @@
+//go:build goexperiment.arenas
+
+package arena
+
+import (
+	stdarena "arena"
+)
+
+type Arena struct {
+	arena *stdarena.Arena
+}
+
+func NewArena() *Arena {
+	return &Arena{arena: stdarena.NewArena()}
+}
+
+func (a *Arena) Free() {
+	a.arena.Free()
+}
+
+func New[T any](a *Arena) *T {
+	return stdarena.New[T](a.arena)
+}
+
+func MakeSlice[T any](a *Arena, len int, cap int) []T {
+	return stdarena.MakeSlice[T](a.arena, len, cap)
+}
+
+func Clone[T any](s T) T {
+	return stdarena.Clone(s)
+}
@@ -0,0 +1,89 @@
+//go:build !goexperiment.arenas
+
+package arena
+
+import (
+	"reflect"
+	"unsafe"
+
+	"golang_course/lessons/allocator/allocators"
+)
+
+const chunkSize = 1 << 16
+
+type Arena struct {
+	allocator *allocators.LinearAllocator
+}
+
+func NewArena() *Arena {
+	allocator, _ := allocators.NewChunkedLinearAllocator(chunkSize)
+	return &Arena{allocator: allocator}
+}
+
+// Free makes memory of the arena available for reuse,
+// values allocated from it must not be used after that
+func (a *Arena) Free() {
+	a.allocator.Free()
+}
+
+func New[T any](a *Arena) *T {
+	valueType := reflect.TypeFor[T]()
+	if valueType.Size() == 0 || allocators.HasPointers(valueType) {
+		return new(T)
+	}
+
+	value, err := allocators.New[T](a.allocator)
+	if err != nil {
+		panic("arena: " + err.Error())
+	}
+
+	return value
+}
+
+func MakeSlice[T any](a *Arena, len int, cap int) []T {
+	if len < 0 || len > cap {
+		panic("arena: incorrect slice length or capacity")
+	}
+
+	valueType := reflect.TypeFor[T]()
+	if cap == 0 || valueType.Size() == 0 || allocators.HasPointers(valueType) {
+		return make([]T, len, cap)
+	}
+
+	pointer, err := a.allocator.AllocateAligned(cap*int(valueType.Size()), valueType.Align())
+	if err != nil {
+		panic("arena: " + err.Error())
+	}
+
+	slice := unsafe.Slice((*T)(pointer), cap)
+	clear(slice)
+	return slice[:len]
+}
+
+// Clone copies values that could be allocated from an arena to the
+// heap, values with pointers and strings are returned untouched
+func Clone[T any](s T) T {
+	value := reflect.ValueOf(s)
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() || allocators.HasPointers(value.Type().Elem()) {
+			return s
+		}
+
+		cloned := reflect.New(value.Type().Elem())
+		cloned.Elem().Set(value.Elem())
+		return cloned.Interface().(T)
+	case reflect.Slice:
+		if value.IsNil() || allocators.HasPointers(value.Type().Elem()) {
+			return s
+		}
+
+		cloned := reflect.MakeSlice(value.Type(), value.Len(), value.Cap())
+		reflect.Copy(cloned, value)
+		return cloned.Interface().(T)
+	case reflect.String:
+		return s
+	default:
+		panic("arena: Clone only supports pointers, slices and strings")
+	}
+}
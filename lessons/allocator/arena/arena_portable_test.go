@@ -0,0 +1,29 @@
+//go:build !goexperiment.arenas
+
+package arena
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortableArenaPlacement(t *testing.T) {
+	a := NewArena()
+	defer a.Free()
+
+	_ = New[data](a)
+	_ = MakeSlice[int64](a, 0, 16)
+	assert.Equal(t, int(unsafe.Sizeof(data{}))+16*8, a.allocator.Stats().Used)
+
+	_ = New[operations](a)
+	_ = MakeSlice[*int](a, 0, 16)
+	assert.Equal(t, int(unsafe.Sizeof(data{}))+16*8, a.allocator.Stats().Used)
+
+	value := New[data](a)
+	assert.NotSame(t, value, Clone(value))
+
+	withPointers := New[operations](a)
+	assert.Same(t, withPointers, Clone(withPointers))
+}
@@ -0,0 +1,67 @@
+package arena
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type data struct {
+	deposit int
+	credit  int
+}
+
+type operations struct {
+	value      int
+	operations []int
+}
+
+func TestNew(t *testing.T) {
+	a := NewArena()
+	defer a.Free()
+
+	value := New[data](a)
+	assert.Equal(t, data{}, *value)
+	value.deposit = 100
+
+	withPointers := New[operations](a)
+	withPointers.operations = append(withPointers.operations, 1, 2, 3)
+	assert.Equal(t, []int{1, 2, 3}, withPointers.operations)
+
+	empty := New[struct{}](a)
+	assert.NotNil(t, empty)
+}
+
+func TestMakeSlice(t *testing.T) {
+	a := NewArena()
+	defer a.Free()
+
+	slice := MakeSlice[int32](a, 5, 10)
+	assert.Len(t, slice, 5)
+	assert.Equal(t, 10, cap(slice))
+	assert.Equal(t, []int32{0, 0, 0, 0, 0}, slice)
+
+	strings := MakeSlice[string](a, 2, 2)
+	strings[0] = "value"
+	assert.Equal(t, []string{"value", ""}, strings)
+
+	assert.Panics(t, func() { MakeSlice[int](a, 2, 1) })
+}
+
+func TestClone(t *testing.T) {
+	a := NewArena()
+
+	value := New[data](a)
+	value.deposit = 100
+	slice := MakeSlice[int](a, 2, 4)
+	slice[1] = 200
+
+	clonedValue := Clone(value)
+	clonedSlice := Clone(slice)
+	a.Free()
+
+	assert.Equal(t, data{deposit: 100}, *clonedValue)
+	assert.Equal(t, []int{0, 200}, clonedSlice)
+	assert.Equal(t, "string", Clone("string"))
+	assert.Panics(t, func() { Clone(100) })
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"golang_course/lessons/allocator/allocators"
+)
+
+// implementation is in allocators/buddy.go
+
+func main() {
+	const KB = 1 << 10
+	allocator, err := allocators.NewBuddyAllocator(KB, 16)
+	if err != nil {
+		// handling...
+	}
+
+	defer allocator.Free()
+
+	pointer1, _ := allocator.Allocate(2)   // 16 bytes block
+	pointer2, _ := allocator.Allocate(100) // 128 bytes block
+
+	allocators.Store[int16](pointer1, 100)
+	allocators.Store[int32](pointer2, 200)
+
+	value1 := allocators.Load[int16](pointer1)
+	value2 := allocators.Load[int32](pointer2)
+	fmt.Println("value1:", value1)
+	fmt.Println("value2:", value2)
+
+	fmt.Println("address1:", pointer1)
+	fmt.Println("address2:", pointer2)
+
+	// any order of deallocation, buddies are merged back
+	allocator.Deallocate(pointer1)
+	allocator.Deallocate(pointer2)
+
+	pointer3, _ := allocator.Allocate(KB)
+	fmt.Println("address3:", pointer3)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// go run ./lessons/allocator/escape_report ./lessons/allocator/allocation_2
+// go run ./lessons/allocator/escape_report -json ./lessons/allocator/... > old.json
+// go run ./lessons/allocator/escape_report -diff old.json ./lessons/allocator/...
+
+func main() {
+	jsonOutput := flag.Bool("json", false, "print records as JSON")
+	all := flag.Bool("all", false, "print values that don't escape")
+	tests := flag.Bool("test", false, "compile test files of a package")
+	noInline := flag.Bool("l", false, "disable inlining")
+	diffWith := flag.String("diff", "", "JSON of a previous run to compare with")
+	input := flag.String("input", "", "file with compiler output instead of running the compiler")
+	flag.Parse()
+
+	if err := run(*jsonOutput, *all, *tests, *noInline, *diffWith, *input, flag.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(jsonOutput, all, tests, noInline bool, diffWith, input string, packages []string) error {
+	var output io.Reader
+	if input != "" {
+		file, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+		output = file
+	} else {
+		compilerOutput, err := compile(packages, tests, noInline)
+		if err != nil {
+			return err
+		}
+
+		output = bytes.NewReader(compilerOutput)
+	}
+
+	records, err := Parse(output)
+	if err != nil {
+		return err
+	}
+
+	if !all {
+		records = escaping(records)
+	}
+
+	switch {
+	case diffWith != "":
+		file, err := os.Open(diffWith)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+		old, err := ReadJSON(file)
+		if err != nil {
+			return fmt.Errorf("incorrect previous run: %w", err)
+		}
+
+		if !all {
+			old = escaping(old)
+		}
+
+		return WriteDiff(os.Stdout, Diff(old, records))
+	case jsonOutput:
+		return WriteJSON(os.Stdout, records)
+	default:
+		return WriteSummary(os.Stdout, records, all)
+	}
+}
+
+// compile runs the compiler and returns its diagnostics,
+// they are replayed from the build cache for cached packages
+func compile(packages []string, tests, noInline bool) ([]byte, error) {
+	gcflags := "-gcflags=-m=2"
+	if noInline {
+		gcflags = "-gcflags=-m=2 -l"
+	}
+
+	args := []string{"build", "-o", os.DevNull, gcflags}
+	if tests {
+		args = []string{"test", "-c", "-o", os.DevNull, gcflags}
+	}
+
+	command := exec.Command("go", append(args, packages...)...)
+	output, err := command.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("compilation failed: %w\n%s", err, output)
+	}
+
+	return output, nil
+}
+
+func escaping(records []Record) []Record {
+	var result []Record
+	for _, record := range records {
+		if record.Kind.Escapes() {
+			result = append(result, record)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Kind string
+
+const (
+	MovedToHeap         Kind = "moved to heap"
+	EscapesToHeap       Kind = "escapes to heap"
+	LeakingParam        Kind = "leaking param"
+	LeakingParamContent Kind = "leaking param content"
+	DoesNotEscape       Kind = "does not escape"
+)
+
+// Escapes reports whether the value is allocated on
+// the heap or can be allocated on the heap by callers
+func (k Kind) Escapes() bool {
+	return k != DoesNotEscape
+}
+
+type Record struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Function string `json:"function"`
+	Variable string `json:"variable"`
+	Kind     Kind   `json:"kind"`
+	Reason   string `json:"reason,omitempty"` // steps of the flow to the heap
+}
+
+var (
+	// file:line:column: message
+	diagnosticRegexp = regexp.MustCompile(`^(.+?):(\d+):(\d+): (.*)$`)
+
+	// explanations of -m=2 end with colon and are followed by indented flow
+	explanationRegexp = regexp.MustCompile(`^(?:parameter )?(.+?) (?:escapes to heap in|leaks to .+ for) (\S+?)(?: with derefs=-?\d+)?:$`)
+	flowStepRegexp    = regexp.MustCompile(`^\s+from .* \(([^()]+)\) at `)
+
+	summaryRegexps = []struct {
+		kind   Kind
+		regexp *regexp.Regexp
+	}{
+		{MovedToHeap, regexp.MustCompile(`^moved to heap: (.+)$`)},
+		{LeakingParamContent, regexp.MustCompile(`^leaking param content: (\S+)`)},
+		{LeakingParam, regexp.MustCompile(`^leaking param: (\S+)`)},
+		{EscapesToHeap, regexp.MustCompile(`^(.+) escapes to heap$`)},
+		{DoesNotEscape, regexp.MustCompile(`^(.+) does not escape$`)},
+	}
+)
+
+// generated main of test binaries
+const testMainFile = "_testmain.go"
+
+type explanation struct {
+	function string
+	steps    []string
+}
+
+// Parse reads output of the compiler with -m=2 (or -m) flag, records
+// without explanation (-m) have no reason. Functions are resolved
+// by source files if they are available.
+func Parse(reader io.Reader) ([]Record, error) {
+	var records []Record
+	var current *explanation
+	explanations := make(map[string]*explanation)
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		matches := diagnosticRegexp.FindStringSubmatch(scanner.Text())
+		if matches == nil {
+			// package headers like "# package"
+			continue
+		}
+
+		if matches[1] == testMainFile {
+			continue
+		}
+
+		position, message := strings.Join(matches[1:4], ":"), matches[4]
+		if strings.HasPrefix(message, " ") {
+			if step := flowStepRegexp.FindStringSubmatch(message); step != nil && current != nil {
+				current.steps = append(current.steps, step[1])
+			}
+
+			continue
+		}
+
+		if match := explanationRegexp.FindStringSubmatch(message); match != nil {
+			current = &explanation{function: match[2]}
+			explanations[position] = current
+			continue
+		}
+
+		current = nil
+		for _, summary := range summaryRegexps {
+			match := summary.regexp.FindStringSubmatch(message)
+			if match == nil {
+				continue
+			}
+
+			line, _ := strconv.Atoi(matches[2])
+			column, _ := strconv.Atoi(matches[3])
+			records = append(records, Record{
+				File:     matches[1],
+				Line:     line,
+				Column:   column,
+				Variable: match[1],
+				Kind:     summary.kind,
+			})
+
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	resolver := newFunctionResolver()
+	for idx := range records {
+		record := &records[idx]
+		position := record.File + ":" + strconv.Itoa(record.Line) + ":" + strconv.Itoa(record.Column)
+		if explanation, found := explanations[position]; found {
+			record.Function = explanation.function
+			record.Reason = strings.Join(compact(explanation.steps), ", ")
+		}
+
+		if function := resolver.function(record.File, record.Line); function != "" {
+			record.Function = function
+		}
+	}
+
+	return records, nil
+}
+
+// compact removes consecutive duplicates
+func compact(steps []string) []string {
+	var result []string
+	for _, step := range steps {
+		if len(result) == 0 || result[len(result)-1] != step {
+			result = append(result, step)
+		}
+	}
+
+	return result
+}
+
+type functionRange struct {
+	name       string
+	start, end int
+}
+
+// functionResolver finds top-level functions (closures
+// belong to them) by lines of parsed source files
+type functionResolver struct {
+	files map[string][]functionRange
+}
+
+func newFunctionResolver() *functionResolver {
+	return &functionResolver{files: make(map[string][]functionRange)}
+}
+
+func (r *functionResolver) function(file string, line int) string {
+	ranges, found := r.files[file]
+	if !found {
+		ranges = parseFunctions(file)
+		r.files[file] = ranges
+	}
+
+	for _, function := range ranges {
+		if function.start <= line && line <= function.end {
+			return function.name
+		}
+	}
+
+	return ""
+}
+
+func parseFunctions(file string) []functionRange {
+	fileSet := token.NewFileSet()
+	parsed, err := parser.ParseFile(fileSet, file, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	var ranges []functionRange
+	for _, declaration := range parsed.Decls {
+		function, ok := declaration.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+
+		ranges = append(ranges, functionRange{
+			name:  functionName(function),
+			start: fileSet.Position(function.Pos()).Line,
+			end:   fileSet.Position(function.End()).Line,
+		})
+	}
+
+	return ranges
+}
+
+// functionName returns names in the compiler format: function or (*Type).Method
+func functionName(function *ast.FuncDecl) string {
+	if function.Recv == nil || len(function.Recv.List) == 0 {
+		return function.Name.Name
+	}
+
+	receiver := function.Recv.List[0].Type
+	if pointer, ok := receiver.(*ast.StarExpr); ok {
+		return "(*" + typeName(pointer.X) + ")." + function.Name.Name
+	}
+
+	return typeName(receiver) + "." + function.Name.Name
+}
+
+func typeName(expression ast.Expr) string {
+	switch expression := expression.(type) {
+	case *ast.Ident:
+		return expression.Name
+	case *ast.IndexExpr:
+		return typeName(expression.X)
+	case *ast.IndexListExpr:
+		return typeName(expression.X)
+	default:
+		return "?"
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+type functionSummary struct {
+	file     string
+	function string
+	escaping int
+	records  []Record
+}
+
+// WriteSummary prints records grouped by functions, values that
+// don't escape are counted, but printed only if all is set
+func WriteSummary(w io.Writer, records []Record, all bool) error {
+	var summaries []*functionSummary
+	byFunction := make(map[[2]string]*functionSummary)
+	for _, record := range records {
+		key := [2]string{record.File, record.Function}
+		summary, found := byFunction[key]
+		if !found {
+			summary = &functionSummary{file: record.File, function: record.Function}
+			byFunction[key] = summary
+			summaries = append(summaries, summary)
+		}
+
+		if record.Kind.Escapes() {
+			summary.escaping++
+		}
+
+		if record.Kind.Escapes() || all {
+			summary.records = append(summary.records, record)
+		}
+	}
+
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].file != summaries[j].file {
+			return summaries[i].file < summaries[j].file
+		}
+
+		return summaries[i].function < summaries[j].function
+	})
+
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, summary := range summaries {
+		if len(summary.records) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(writer, "%s (%s): %d escaping\n", summary.function, summary.file, summary.escaping)
+		sort.SliceStable(summary.records, func(i, j int) bool {
+			left, right := summary.records[i], summary.records[j]
+			if left.Line != right.Line {
+				return left.Line < right.Line
+			}
+
+			return left.Column < right.Column
+		})
+
+		for _, record := range summary.records {
+			fmt.Fprintf(writer, "\t%d:%d\t%s\t%s\t%s\n", record.Line, record.Column, record.Variable, record.Kind, record.Reason)
+		}
+	}
+
+	return writer.Flush()
+}
+
+func WriteJSON(w io.Writer, records []Record) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+func ReadJSON(r io.Reader) ([]Record, error) {
+	var records []Record
+	if err := json.NewDecoder(r).Decode(&records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+type Change struct {
+	Added  bool
+	Record Record
+}
+
+// recordKey ignores positions, because they are shifted by refactoring
+type recordKey struct {
+	file     string
+	function string
+	variable string
+	kind     Kind
+}
+
+func keyOf(record Record) recordKey {
+	return recordKey{record.File, record.Function, record.Variable, record.Kind}
+}
+
+// Diff returns records that were removed from old run and added in
+// new run, same values are counted, because they can repeat in a function
+func Diff(old, new []Record) []Change {
+	counts := make(map[recordKey]int)
+	for _, record := range old {
+		counts[keyOf(record)]++
+	}
+
+	var changes []Change
+	for _, record := range new {
+		key := keyOf(record)
+		if counts[key] > 0 {
+			counts[key]--
+			continue
+		}
+
+		changes = append(changes, Change{Added: true, Record: record})
+	}
+
+	for _, record := range old {
+		key := keyOf(record)
+		if counts[key] > 0 {
+			counts[key]--
+			changes = append(changes, Change{Record: record})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		left, right := changes[i].Record, changes[j].Record
+		if left.File != right.File {
+			return left.File < right.File
+		}
+
+		return left.Line < right.Line
+	})
+
+	return changes
+}
+
+func WriteDiff(w io.Writer, changes []Change) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, change := range changes {
+		sign := "-"
+		if change.Added {
+			sign = "+"
+		}
+
+		record := change.Record
+		fmt.Fprintf(writer, "%s\t%s:%d\t%s\t%s\t%s\t%s\n", sign, record.File, record.Line, record.Function, record.Variable, record.Kind, record.Reason)
+	}
+
+	return writer.Flush()
+}
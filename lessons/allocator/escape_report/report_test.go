@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const compilerOutput = `# golang_course/lessons/allocator/allocation_2
+testdata/main.go:7:6: can inline getResult with cost 8 as: func() *int { result := 200; return &result }
+testdata/main.go:8:2: result escapes to heap in getResult:
+testdata/main.go:8:2:   flow: ~r0 ← &result:
+testdata/main.go:8:2:     from &result (address-of) at testdata/main.go:9:9
+testdata/main.go:8:2:     from return &result (return) at testdata/main.go:9:2
+testdata/main.go:8:2: moved to heap: result
+testdata/main.go:12:17: parameter v leaks to {heap} for printValue with derefs=0:
+testdata/main.go:12:17:   flow: {heap} ← v:
+testdata/main.go:12:17:     from fmt.Println(v) (call parameter) at testdata/main.go:13:13
+testdata/main.go:12:17: leaking param: v
+testdata/main.go:17:9: (*Buffer).Bytes ignoring self-assignment in b.data = b.data[:0]
+testdata/main.go:17:7: leaking param content: b
+testdata/main.go:21:13: 10 does not escape
+_testmain.go:46:42: testdeps.TestDeps{} escapes to heap
+`
+
+func TestParse(t *testing.T) {
+	records, err := Parse(strings.NewReader(compilerOutput))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Record{
+		{File: "testdata/main.go", Line: 8, Column: 2, Function: "getResult", Variable: "result", Kind: MovedToHeap, Reason: "address-of, return"},
+		{File: "testdata/main.go", Line: 12, Column: 17, Function: "printValue", Variable: "v", Kind: LeakingParam, Reason: "call parameter"},
+		{File: "testdata/main.go", Line: 17, Column: 7, Function: "(*Buffer).Reset", Variable: "b", Kind: LeakingParamContent},
+		{File: "testdata/main.go", Line: 21, Column: 13, Function: "main", Variable: "10", Kind: DoesNotEscape},
+	}, records)
+}
+
+func TestDiff(t *testing.T) {
+	old := []Record{
+		{File: "main.go", Line: 8, Function: "getResult", Variable: "result", Kind: MovedToHeap},
+		{File: "main.go", Line: 20, Function: "main", Variable: "10", Kind: EscapesToHeap},
+		{File: "main.go", Line: 21, Function: "main", Variable: "10", Kind: EscapesToHeap},
+	}
+
+	new := []Record{
+		{File: "main.go", Line: 10, Function: "getResult", Variable: "result", Kind: MovedToHeap},
+		{File: "main.go", Line: 22, Function: "main", Variable: "10", Kind: EscapesToHeap},
+		{File: "main.go", Line: 30, Function: "main", Variable: "buffer", Kind: MovedToHeap},
+	}
+
+	assert.Equal(t, []Change{
+		{Added: false, Record: old[1]},
+		{Added: true, Record: new[2]},
+	}, Diff(old, new))
+}
+
+func TestJSON(t *testing.T) {
+	records := []Record{
+		{File: "main.go", Line: 8, Column: 2, Function: "getResult", Variable: "&result", Kind: MovedToHeap, Reason: "return"},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, WriteJSON(&buffer, records))
+	assert.Contains(t, buffer.String(), `"&result"`)
+
+	decoded, err := ReadJSON(&buffer)
+	require.NoError(t, err)
+	assert.Equal(t, records, decoded)
+}
+
+func TestWriteSummary(t *testing.T) {
+	records, err := Parse(strings.NewReader(compilerOutput))
+	require.NoError(t, err)
+
+	var buffer bytes.Buffer
+	require.NoError(t, WriteSummary(&buffer, records, false))
+
+	summary := buffer.String()
+	assert.Contains(t, summary, "getResult (testdata/main.go): 1 escaping")
+	assert.Contains(t, summary, "address-of, return")
+	assert.NotContains(t, summary, "main (testdata/main.go)")
+}
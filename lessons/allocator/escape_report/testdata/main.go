@@ -0,0 +1,22 @@
+package main
+
+import "fmt"
+
+type Buffer struct{ data []byte }
+
+func getResult() *int {
+	result := 200
+	return &result
+}
+
+func printValue(v interface{}) {
+	fmt.Println(v)
+}
+
+// Reset keeps capacity
+func (b *Buffer) Reset() { b.data = b.data[:0] }
+
+func main() {
+	_ = getResult()
+	printValue(10)
+}
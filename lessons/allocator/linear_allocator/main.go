@@ -1,57 +1,16 @@
 package main
 
 import (
-	"errors"
 	"fmt"
-	"unsafe"
-)
-
-type LinearAllocator struct {
-	data []byte
-}
-
-func NewLinearAllocator(capacity int) (LinearAllocator, error) {
-	if capacity <= 0 {
-		return LinearAllocator{}, errors.New("incorrect capacity")
-	}
-
-	return LinearAllocator{
-		data: make([]byte, 0, capacity),
-	}, nil
-}
 
-func (a *LinearAllocator) Allocate(size int) (unsafe.Pointer, error) {
-	previousLength := len(a.data)
-	newLength := previousLength + size
-
-	if newLength > cap(a.data) {
-		// can increase capacity
-		return nil, errors.New("not enough memory")
-	}
-
-	a.data = a.data[:newLength]
-	pointer := unsafe.Pointer(&a.data[previousLength])
-	return pointer, nil
-}
-
-// not supported by this kind of allocator
-// func (a *LinearAllocator) Deallocate(pointer unsafe.Pointer) error {}
-
-func (a *LinearAllocator) Free() {
-	a.data = a.data[:0]
-}
-
-func store[T any](pointer unsafe.Pointer, value T) {
-	*(*T)(pointer) = value
-}
+	"golang_course/lessons/allocator/allocators"
+)
 
-func load[T any](pointer unsafe.Pointer) T {
-	return *(*T)(pointer)
-}
+// implementation is in allocators/linear.go
 
 func main() {
 	const MB = 1 << 20
-	allocator, err := NewLinearAllocator(MB)
+	allocator, err := allocators.NewLinearAllocator(MB)
 	if err != nil {
 		// handling...
 	}
@@ -59,16 +18,35 @@ func main() {
 	defer allocator.Free()
 
 	pointer1, _ := allocator.Allocate(2)
-	pointer2, _ := allocator.Allocate(4)
+	pointer2, _ := allocator.AllocateAligned(4, 4) // with padding
 
-	store[int16](pointer1, 100)
-	store[int32](pointer2, 200)
+	allocators.Store[int16](pointer1, 100)
+	allocators.Store[int32](pointer2, 200)
 
-	value1 := load[int16](pointer1)
-	value2 := load[int32](pointer2)
+	value1 := allocators.Load[int16](pointer1)
+	value2 := allocators.Load[int32](pointer2)
 	fmt.Println("value1:", value1)
 	fmt.Println("value2:", value2)
 
 	fmt.Println("address1:", pointer1)
 	fmt.Println("address2:", pointer2)
+
+	chunked, err := allocators.NewChunkedLinearAllocator(4)
+	if err != nil {
+		// handling...
+	}
+
+	defer chunked.Free()
+
+	pointer3, _ := chunked.Allocate(4)
+	pointer4, _ := chunked.Allocate(8) // new chunk
+
+	allocators.Store[int32](pointer3, 300)
+	allocators.Store[int64](pointer4, 400)
+
+	fmt.Println("value3:", allocators.Load[int32](pointer3))
+	fmt.Println("value4:", allocators.Load[int64](pointer4))
+
+	pointer5, _ := allocators.New[int64](allocator)
+	fmt.Println("address5:", pointer5)
 }
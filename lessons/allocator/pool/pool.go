@@ -0,0 +1,90 @@
+package pool
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Pool is a typed wrapper around sync.Pool, T should be
+// a pointer-like type to avoid allocations on Put
+type Pool[T any] struct {
+	pool  sync.Pool
+	reset func(T)
+
+	// objects bigger than maxSize are dropped on Put
+	size    func(T) int
+	maxSize int
+
+	gets    atomic.Int64
+	misses  atomic.Int64
+	puts    atomic.Int64
+	dropped atomic.Int64
+}
+
+type Stats struct {
+	Gets    int64
+	Hits    int64
+	Misses  int64
+	Puts    int64
+	Dropped int64
+}
+
+func NewPool[T any](create func() T, reset func(T)) (*Pool[T], error) {
+	if create == nil || reset == nil {
+		return nil, errors.New("incorrect arguments")
+	}
+
+	p := &Pool[T]{reset: reset}
+	p.pool.New = func() any {
+		p.misses.Add(1)
+		return create()
+	}
+
+	return p, nil
+}
+
+// NewLimitedPool creates a pool that doesn't keep objects
+// bigger than maxSize (for example, grown buffers)
+func NewLimitedPool[T any](create func() T, reset func(T), size func(T) int, maxSize int) (*Pool[T], error) {
+	if size == nil || maxSize <= 0 {
+		return nil, errors.New("incorrect arguments")
+	}
+
+	p, err := NewPool(create, reset)
+	if err != nil {
+		return nil, err
+	}
+
+	p.size = size
+	p.maxSize = maxSize
+	return p, nil
+}
+
+func (p *Pool[T]) Get() T {
+	p.gets.Add(1)
+	return p.pool.Get().(T)
+}
+
+func (p *Pool[T]) Put(value T) {
+	p.puts.Add(1)
+	if p.size != nil && p.size(value) > p.maxSize {
+		p.dropped.Add(1)
+		return
+	}
+
+	p.reset(value)
+	p.pool.Put(value)
+}
+
+func (p *Pool[T]) Stats() Stats {
+	gets := p.gets.Load()
+	misses := p.misses.Load()
+	return Stats{
+		Gets:    gets,
+		Hits:    gets - misses,
+		Misses:  misses,
+		Puts:    p.puts.Load(),
+		Dropped: p.dropped.Load(),
+	}
+}
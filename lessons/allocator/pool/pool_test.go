@@ -1,10 +1,14 @@
-package main
+package pool
 
-// go test -bench=. pool_test.go -benchmem
+// go test -bench=. -benchmem
 
 import (
+	"bytes"
 	"sync"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type Person struct {
@@ -42,9 +46,72 @@ func BenchmarkWithPool(b *testing.B) {
 	}
 }
 
+func BenchmarkWithGenericPool(b *testing.B) {
+	pool, _ := NewPool(
+		func() *Person { return new(Person) },
+		func(person *Person) { person.name = "" },
+	)
+
+	for i := 0; i < b.N; i++ {
+		person := pool.Get()
+		person.name = "Ivan"
+		gPerson = person
+		pool.Put(person)
+	}
+}
+
 func BenchmarkWithoutPool(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		person := &Person{name: "Ivan"}
 		gPerson = person
 	}
 }
+
+func TestPoolReset(t *testing.T) {
+	_, err := NewPool[*Person](nil, func(*Person) {})
+	assert.Error(t, err)
+	_, err = NewPool(func() *Person { return new(Person) }, nil)
+	assert.Error(t, err)
+
+	pool, err := NewPool(
+		func() *Person { return new(Person) },
+		func(person *Person) { person.name = "" },
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		person := pool.Get()
+		assert.Empty(t, person.name)
+		person.name = "Ivan"
+		pool.Put(person)
+	}
+
+	stats := pool.Stats()
+	assert.Equal(t, int64(100), stats.Gets)
+	assert.Equal(t, int64(100), stats.Puts)
+	assert.Equal(t, stats.Gets, stats.Hits+stats.Misses)
+	assert.Positive(t, stats.Misses)
+}
+
+func TestLimitedPool(t *testing.T) {
+	const maxSize = 1 << 10
+	pool, err := NewLimitedPool(
+		func() *bytes.Buffer { return new(bytes.Buffer) },
+		func(buffer *bytes.Buffer) { buffer.Reset() },
+		func(buffer *bytes.Buffer) int { return buffer.Cap() },
+		maxSize,
+	)
+	require.NoError(t, err)
+
+	small := pool.Get()
+	small.WriteString("small")
+	pool.Put(small)
+
+	big := pool.Get()
+	big.Grow(2 * maxSize)
+	pool.Put(big)
+
+	stats := pool.Stats()
+	assert.Equal(t, int64(2), stats.Puts)
+	assert.Equal(t, int64(1), stats.Dropped)
+}
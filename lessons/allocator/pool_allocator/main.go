@@ -1,93 +1,32 @@
 package main
 
 import (
-	"errors"
 	"fmt"
+	"os"
 	"unsafe"
-)
-
-type PoolAllocator struct {
-	objectPool  []byte
-	freeObjects map[unsafe.Pointer]struct{}
-	objectSize  int
-}
-
-func NewPoolAllocator(capacity int, objectSize int) (PoolAllocator, error) {
-	if capacity <= 0 || objectSize <= 0 || capacity%objectSize != 0 {
-		return PoolAllocator{}, errors.New("incorrect argumnets")
-	}
-
-	allocator := PoolAllocator{
-		objectPool:  make([]byte, capacity),
-		freeObjects: make(map[unsafe.Pointer]struct{}, capacity/objectSize),
-		objectSize:  objectSize,
-	}
-
-	allocator.resetMemoryState()
-	return allocator, nil
-}
-
-func (a *PoolAllocator) Allocate() (unsafe.Pointer, error) {
-	if len(a.freeObjects) == 0 {
-		// can increase capacity
-		return nil, errors.New("not enough memory")
-	}
-
-	var pointer unsafe.Pointer
-	for freePointer := range a.freeObjects {
-		pointer = freePointer
-		break
-	}
-
-	return pointer, nil
-}
 
-func (a *PoolAllocator) Deallocate(pointer unsafe.Pointer) error {
-	if pointer == nil {
-		return errors.New("incorrect pointer")
-	}
-
-	// potentionally incorrect pointer
-	a.freeObjects[pointer] = struct{}{}
-	return nil
-}
-
-func (a *PoolAllocator) Free() {
-	a.resetMemoryState()
-}
-
-func (a *PoolAllocator) resetMemoryState() {
-	for offset := 0; offset < len(a.objectPool); offset += a.objectSize {
-		pointer := unsafe.Pointer(&a.objectPool[offset])
-		a.freeObjects[pointer] = struct{}{}
-	}
-}
-
-func store[T any](pointer unsafe.Pointer, value T) {
-	*(*T)(pointer) = value
-}
+	"golang_course/lessons/allocator/allocators"
+)
 
-func load[T any](pointer unsafe.Pointer) T {
-	return *(*T)(pointer)
-}
+// implementation is in allocators/pool.go
 
 func main() {
 	const KB = 1 << 10
-	allocator, err := NewPoolAllocator(KB, 4)
+	allocator, err := allocators.NewPoolAllocator(KB, 4)
 	if err != nil {
 		// handling...
 	}
 
 	defer allocator.Free()
 
-	pointer1, _ := allocator.Allocate()
-	pointer2, _ := allocator.Allocate()
+	pointer1, _ := allocator.Allocate(4)
+	pointer2, _ := allocator.Allocate(4)
 
-	store[int32](pointer1, 100)
-	store[int32](pointer2, 200)
+	allocators.Store[int32](pointer1, 100)
+	allocators.Store[int32](pointer2, 200)
 
-	value1 := load[int32](pointer1)
-	value2 := load[int32](pointer2)
+	value1 := allocators.Load[int32](pointer1)
+	value2 := allocators.Load[int32](pointer2)
 	fmt.Println("value1:", value1)
 	fmt.Println("value2:", value2)
 
@@ -96,4 +35,16 @@ func main() {
 
 	allocator.Deallocate(pointer1)
 	allocator.Deallocate(pointer2)
+
+	pointer3, _ := allocators.New[int32](allocator)
+	fmt.Println("address3:", pointer3)
+	allocator.Deallocate(unsafe.Pointer(pointer3))
+
+	// usage report to choose capacity
+	tracked := allocators.NewStatsAllocator(allocator)
+	for i := 0; i < 100; i++ {
+		_, _ = tracked.Allocate(3)
+	}
+
+	_ = allocators.WriteReport(os.Stdout, tracked.Stats())
 }
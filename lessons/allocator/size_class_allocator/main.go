@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"golang_course/lessons/allocator/allocators"
+)
+
+// implementation is in allocators/sizeclass.go
+
+func main() {
+	allocator := allocators.NewSizeClassAllocator()
+	defer allocator.Free()
+
+	tracked := allocators.NewStatsAllocator(allocator)
+
+	// the same sizes as in allocations_size: 33 + 33 + 66 bytes
+	_, _ = tracked.Allocate(33)
+	_, _ = tracked.Allocate(33)
+	_, _ = tracked.Allocate(66)
+
+	// more than 32 KB - whole pages
+	_, _ = tracked.Allocate(32769)
+
+	for _, class := range allocator.ClassStats() {
+		fmt.Printf("class %d: pages=%d spans=%d objects=%d tail waste=%d\n",
+			class.Size, class.Pages, class.Spans, class.Objects, class.TailWaste)
+	}
+
+	_ = allocators.WriteReport(os.Stdout, tracked.Stats())
+}
@@ -0,0 +1,141 @@
+// Package cleanup runs cleanup of owned resources deterministically
+// on Close and reports resources collected by GC without Close
+package cleanup
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const maxStackDepth = 32
+
+var ErrClosed = errors.New("resource is already closed")
+
+// Leak describes a resource that was collected without Close
+type Leak struct {
+	Name  string
+	Stack string // stack of registration
+}
+
+// LogLeak is the default leak reporter
+func LogLeak(leak Leak) {
+	log.Printf("leak: %s was collected without Close, registered at:\n%s", leak.Name, leak.Stack)
+}
+
+type Registry struct {
+	report func(Leak)
+	live   atomic.Int64
+	leaked atomic.Int64
+}
+
+// NewRegistry creates a registry with the leak reporter, reporter is
+// called from the finalizer goroutine, so it must not block for long
+func NewRegistry(report func(Leak)) *Registry {
+	if report == nil {
+		report = LogLeak
+	}
+
+	return &Registry{report: report}
+}
+
+// Live returns the number of registered and not closed resources
+func (r *Registry) Live() int {
+	return int(r.live.Load())
+}
+
+// Leaked returns the number of resources collected without Close
+func (r *Registry) Leaked() int {
+	return int(r.leaked.Load())
+}
+
+// Resource must be stored inside of its owner, it doesn't
+// reference the owner, so the owner can be collected
+type Resource struct {
+	mutex    sync.Mutex
+	closed   bool
+	name     string
+	cleanup  func() error
+	stack    []uintptr
+	registry *Registry
+}
+
+// Register attaches a finalizer to owner that reports a leak and runs
+// cleanup if Close wasn't called. Cleanup must not reference owner,
+// otherwise owner is never collected. Owner must not have other
+// finalizers and must be allocated (not tiny) object, because tiny
+// objects without pointers can be never finalized.
+func Register[T any](registry *Registry, owner *T, name string, cleanup func() error) *Resource {
+	stack := make([]uintptr, maxStackDepth)
+	stack = stack[:runtime.Callers(2, stack)]
+
+	resource := &Resource{
+		name:     name,
+		cleanup:  cleanup,
+		stack:    stack,
+		registry: registry,
+	}
+
+	registry.live.Add(1)
+	runtime.SetFinalizer(owner, func(*T) {
+		resource.finalize()
+	})
+
+	return resource
+}
+
+// Close runs cleanup, only the first call does it
+func (r *Resource) Close() error {
+	if !r.markClosed() {
+		return ErrClosed
+	}
+
+	return r.cleanup()
+}
+
+func (r *Resource) finalize() {
+	if !r.markClosed() {
+		return
+	}
+
+	r.registry.leaked.Add(1)
+	r.registry.report(Leak{
+		Name:  r.name,
+		Stack: formatStack(r.stack),
+	})
+
+	// the resource is released anyway, there is nobody to return error
+	_ = r.cleanup()
+}
+
+func (r *Resource) markClosed() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.closed {
+		return false
+	}
+
+	r.closed = true
+	r.registry.live.Add(-1)
+	return true
+}
+
+func formatStack(stack []uintptr) string {
+	var builder strings.Builder
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&builder, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+
+		if !more {
+			break
+		}
+	}
+
+	return builder.String()
+}
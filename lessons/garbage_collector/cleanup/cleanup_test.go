@@ -0,0 +1,86 @@
+package cleanup
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type file struct {
+	resource *Resource
+	buffer   []byte
+}
+
+func newFile(registry *Registry, name string, closed *atomic.Int32) *file {
+	f := &file{buffer: make([]byte, 64)}
+	f.resource = Register(registry, f, name, func() error {
+		closed.Add(1)
+		return nil
+	})
+
+	return f
+}
+
+func TestClose(t *testing.T) {
+	registry := NewRegistry(func(leak Leak) {
+		t.Errorf("unexpected leak: %s", leak.Name)
+	})
+
+	var closed atomic.Int32
+	f := newFile(registry, "file", &closed)
+	assert.Equal(t, 1, registry.Live())
+
+	require.NoError(t, f.resource.Close())
+	assert.ErrorIs(t, f.resource.Close(), ErrClosed)
+	assert.Equal(t, int32(1), closed.Load())
+	assert.Equal(t, 0, registry.Live())
+
+	f = nil
+	runtime.GC()
+	runtime.GC()
+	assert.Equal(t, 0, registry.Leaked())
+}
+
+func TestCloseError(t *testing.T) {
+	registry := NewRegistry(nil)
+	owner := new(file)
+	resource := Register(registry, owner, "owner", func() error {
+		return errors.New("close error")
+	})
+
+	assert.EqualError(t, resource.Close(), "close error")
+	runtime.KeepAlive(owner)
+}
+
+func TestLeak(t *testing.T) {
+	leaks := make(chan Leak, 1)
+	registry := NewRegistry(func(leak Leak) {
+		leaks <- leak
+	})
+
+	var closed atomic.Int32
+	_ = newFile(registry, "forgotten file", &closed)
+
+	for attempt := 0; attempt < 10; attempt++ {
+		runtime.GC()
+
+		select {
+		case leak := <-leaks:
+			assert.Equal(t, "forgotten file", leak.Name)
+			assert.Contains(t, leak.Stack, "cleanup.newFile")
+			assert.Contains(t, leak.Stack, "cleanup.TestLeak")
+			assert.Equal(t, int32(1), closed.Load())
+			assert.Equal(t, 0, registry.Live())
+			assert.Equal(t, 1, registry.Leaked())
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	t.Fatal("leak wasn't reported")
+}
@@ -0,0 +1,177 @@
+// Package harness runs a workload under different GOGC and memory
+// limit settings and collects GC metrics for comparison
+package harness
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"runtime/debug"
+	"text/tabwriter"
+	"time"
+)
+
+// NoLimit disables the memory limit (the same as default GOMEMLIMIT)
+const NoLimit = math.MaxInt64
+
+// GCOff disables GC by percent (the same as GOGC=off)
+const GCOff = -1
+
+// Default leaves GOGC or the memory limit unchanged, so the zero
+// Config runs with the current settings (GOGC=0 isn't supported)
+const Default = 0
+
+const defaultSampleInterval = time.Millisecond
+
+const (
+	gcCyclesMetric  = "/gc/cycles/total:gc-cycles"
+	gcPausesMetric  = "/sched/pauses/total/gc:seconds"
+	heapGoalMetric  = "/gc/heap/goal:bytes"
+	liveHeapMetric  = "/gc/heap/live:bytes"
+	heapAllocMetric = "/gc/heap/allocs:bytes"
+)
+
+// Config with Default (zero) fields keeps the current settings,
+// non-positive memory limits are treated as Default too
+type Config struct {
+	GOGC        int
+	MemoryLimit int64
+}
+
+type Result struct {
+	Config
+	Duration     time.Duration
+	GCCycles     uint64
+	PauseTotal   time.Duration // estimated by histogram buckets
+	PeakHeapGoal uint64
+	PeakLiveHeap uint64
+	Allocated    uint64
+}
+
+// Matrix returns all combinations of GOGC values and memory limits
+func Matrix(gogcValues []int, memoryLimits []int64) []Config {
+	configs := make([]Config, 0, len(gogcValues)*len(memoryLimits))
+	for _, gogc := range gogcValues {
+		for _, memoryLimit := range memoryLimits {
+			configs = append(configs, Config{GOGC: gogc, MemoryLimit: memoryLimit})
+		}
+	}
+
+	return configs
+}
+
+// Harness runs workloads, heap metrics are sampled with the
+// interval, because they change during the workload, non-positive
+// interval is replaced by 1ms
+type Harness struct {
+	SampleInterval time.Duration
+}
+
+func New() *Harness {
+	return &Harness{SampleInterval: defaultSampleInterval}
+}
+
+// Run executes workload for every config, previous GC settings are restored
+// after each run, runs must not be executed concurrently
+func (h *Harness) Run(workload func(), configs []Config) []Result {
+	results := make([]Result, 0, len(configs))
+	for _, config := range configs {
+		results = append(results, h.run(workload, config))
+	}
+
+	return results
+}
+
+func (h *Harness) run(workload func(), config Config) Result {
+	// start from the clean heap
+	runtime.GC()
+
+	// zero values would mean collecting on every allocation
+	// and the zero memory limit, not default settings
+	if config.GOGC != Default {
+		previousGOGC := debug.SetGCPercent(config.GOGC)
+		defer debug.SetGCPercent(previousGOGC)
+	}
+
+	if config.MemoryLimit > 0 {
+		previousLimit := debug.SetMemoryLimit(config.MemoryLimit)
+		defer debug.SetMemoryLimit(previousLimit)
+	}
+
+	interval := h.SampleInterval
+	if interval <= 0 {
+		interval = defaultSampleInterval
+	}
+
+	before := readSamples()
+	sampler := startSampler(interval)
+
+	start := time.Now()
+	workload()
+	duration := time.Since(start)
+
+	peakHeapGoal, peakLiveHeap := sampler.stop()
+	after := readSamples()
+
+	return Result{
+		Config:       config,
+		Duration:     duration,
+		GCCycles:     after.gcCycles - before.gcCycles,
+		PauseTotal:   after.pauseTotal - before.pauseTotal,
+		PeakHeapGoal: max(peakHeapGoal, after.heapGoal),
+		PeakLiveHeap: max(peakLiveHeap, after.liveHeap),
+		Allocated:    after.allocated - before.allocated,
+	}
+}
+
+// WriteTable prints results as an aligned table
+func WriteTable(w io.Writer, results []Result) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "GOGC\tLIMIT\tDURATION\tGC CYCLES\tPAUSES\tPEAK GOAL\tPEAK LIVE\tALLOCATED\t")
+	for _, result := range results {
+		fmt.Fprintf(writer, "%s\t%s\t%v\t%d\t%v\t%s\t%s\t%s\t\n",
+			formatGOGC(result.GOGC),
+			formatLimit(result.MemoryLimit),
+			result.Duration.Round(time.Microsecond),
+			result.GCCycles,
+			result.PauseTotal.Round(time.Microsecond),
+			formatBytes(result.PeakHeapGoal),
+			formatBytes(result.PeakLiveHeap),
+			formatBytes(result.Allocated),
+		)
+	}
+
+	return writer.Flush()
+}
+
+func formatGOGC(gogc int) string {
+	if gogc < 0 {
+		return "off"
+	} else if gogc == Default {
+		return "default"
+	}
+
+	return fmt.Sprint(gogc)
+}
+
+func formatLimit(limit int64) string {
+	if limit == NoLimit {
+		return "none"
+	} else if limit <= 0 {
+		return "default"
+	}
+
+	return formatBytes(uint64(limit))
+}
+
+func formatBytes(bytes uint64) string {
+	const MB = 1 << 20
+
+	// heap goal is unbounded with GOGC=off and without limit
+	if bytes >= math.MaxInt64/2 {
+		return "inf"
+	}
+
+	return fmt.Sprintf("%.1fMB", float64(bytes)/MB)
+}
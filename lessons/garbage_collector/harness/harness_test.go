@@ -0,0 +1,96 @@
+package harness
+
+import (
+	"bytes"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var sink [][]byte
+
+func workload() {
+	for i := 0; i < 1<<12; i++ {
+		sink = append(sink, make([]byte, 16<<10))
+		if len(sink) == 64 {
+			sink = sink[:0]
+		}
+	}
+
+	sink = nil
+}
+
+func TestMatrix(t *testing.T) {
+	configs := Matrix([]int{50, 100}, []int64{NoLimit, 64 << 20})
+	assert.Equal(t, []Config{
+		{GOGC: 50, MemoryLimit: NoLimit},
+		{GOGC: 50, MemoryLimit: 64 << 20},
+		{GOGC: 100, MemoryLimit: NoLimit},
+		{GOGC: 100, MemoryLimit: 64 << 20},
+	}, configs)
+}
+
+func TestRun(t *testing.T) {
+	previousGOGC := debug.SetGCPercent(100)
+	defer debug.SetGCPercent(previousGOGC)
+
+	results := New().Run(workload, []Config{
+		{GOGC: 10, MemoryLimit: NoLimit},
+		{GOGC: GCOff, MemoryLimit: NoLimit},
+		{GOGC: GCOff, MemoryLimit: 8 << 20},
+	})
+
+	require.Len(t, results, 3)
+	assert.NotZero(t, results[0].GCCycles)
+	assert.Zero(t, results[1].GCCycles)
+	assert.NotZero(t, results[2].GCCycles)
+
+	for _, result := range results {
+		// allocations in mcache aren't flushed to metrics yet
+		assert.Greater(t, result.Allocated, uint64(32<<20))
+		assert.NotZero(t, result.PeakHeapGoal)
+	}
+
+	// settings are restored
+	assert.Equal(t, 100, debug.SetGCPercent(100))
+	assert.Equal(t, int64(NoLimit), debug.SetMemoryLimit(-1))
+}
+
+func TestRunDefaultConfig(t *testing.T) {
+	previousGOGC := debug.SetGCPercent(100)
+	defer debug.SetGCPercent(previousGOGC)
+	previousLimit := debug.SetMemoryLimit(256 << 20)
+	defer debug.SetMemoryLimit(previousLimit)
+
+	// zero values of harness and config keep current settings
+	var harness Harness
+	results := harness.Run(workload, Matrix([]int{Default}, []int64{Default, -1}))
+
+	require.Len(t, results, 2)
+	for _, result := range results {
+		// the zero limit or GOGC=0 would collect on every allocation
+		assert.Less(t, result.GCCycles, uint64(1<<10))
+	}
+
+	assert.Equal(t, 100, debug.SetGCPercent(100))
+	assert.Equal(t, int64(256<<20), debug.SetMemoryLimit(-1))
+}
+
+func TestWriteTable(t *testing.T) {
+	var buffer bytes.Buffer
+	err := WriteTable(&buffer, []Result{
+		{Config: Config{GOGC: GCOff, MemoryLimit: 64 << 20}, GCCycles: 3, PeakLiveHeap: 1 << 20},
+		{Config: Config{}},
+	})
+
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[1], "off")
+	assert.Contains(t, lines[1], "64.0MB")
+	assert.Contains(t, lines[1], "1.0MB")
+	assert.Equal(t, 2, strings.Count(lines[2], "default"))
+}
@@ -0,0 +1,106 @@
+package harness
+
+import (
+	"math"
+	"runtime/metrics"
+	"time"
+)
+
+type samples struct {
+	gcCycles   uint64
+	pauseTotal time.Duration
+	heapGoal   uint64
+	liveHeap   uint64
+	allocated  uint64
+}
+
+func readSamples() samples {
+	descriptions := []metrics.Sample{
+		{Name: gcCyclesMetric},
+		{Name: gcPausesMetric},
+		{Name: heapGoalMetric},
+		{Name: liveHeapMetric},
+		{Name: heapAllocMetric},
+	}
+
+	metrics.Read(descriptions)
+	return samples{
+		gcCycles:   uint64Value(descriptions[0].Value),
+		pauseTotal: histogramTotal(descriptions[1].Value),
+		heapGoal:   uint64Value(descriptions[2].Value),
+		liveHeap:   uint64Value(descriptions[3].Value),
+		allocated:  uint64Value(descriptions[4].Value),
+	}
+}
+
+// unsupported metrics (older Go versions) have KindBad
+func uint64Value(value metrics.Value) uint64 {
+	if value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+
+	return value.Uint64()
+}
+
+// histogramTotal estimates sum of values by middles of buckets
+func histogramTotal(value metrics.Value) time.Duration {
+	if value.Kind() != metrics.KindFloat64Histogram {
+		return 0
+	}
+
+	histogram := value.Float64Histogram()
+
+	var total float64
+	for idx, count := range histogram.Counts {
+		lower, upper := histogram.Buckets[idx], histogram.Buckets[idx+1]
+		switch {
+		case math.IsInf(lower, -1):
+			total += float64(count) * upper
+		case math.IsInf(upper, 1):
+			total += float64(count) * lower
+		default:
+			total += float64(count) * (lower + upper) / 2
+		}
+	}
+
+	return time.Duration(total * float64(time.Second))
+}
+
+type sampler struct {
+	done   chan struct{}
+	result chan [2]uint64
+}
+
+// startSampler tracks peaks of heap goal and live heap until stop
+func startSampler(interval time.Duration) *sampler {
+	s := &sampler{
+		done:   make(chan struct{}),
+		result: make(chan [2]uint64),
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var peakHeapGoal, peakLiveHeap uint64
+		for {
+			select {
+			case <-ticker.C:
+				current := readSamples()
+				peakHeapGoal = max(peakHeapGoal, current.heapGoal)
+				peakLiveHeap = max(peakLiveHeap, current.liveHeap)
+			case <-s.done:
+				s.result <- [2]uint64{peakHeapGoal, peakLiveHeap}
+				return
+			}
+		}
+	}()
+
+	return s
+}
+
+func (s *sampler) stop() (uint64, uint64) {
+	close(s.done)
+	result := <-s.result
+	return result[0], result[1]
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log"
+	"runtime"
+	"time"
+
+	"golang_course/lessons/garbage_collector/cleanup"
+)
+
+// implementation is in cleanup/cleanup.go
+
+var registry = cleanup.NewRegistry(nil)
+
+type Buffer struct {
+	resource *cleanup.Resource
+	data     []byte
+}
+
+func NewBuffer(name string, size int) *Buffer {
+	buffer := &Buffer{data: make([]byte, size)}
+
+	// cleanup mustn't capture buffer
+	buffer.resource = cleanup.Register(registry, buffer, name, func() error {
+		log.Println("released:", name)
+		return nil
+	})
+
+	return buffer
+}
+
+func (b *Buffer) Close() error {
+	return b.resource.Close()
+}
+
+func main() {
+	closed := NewBuffer("closed", 1024)
+	_ = closed.Close()
+
+	_ = NewBuffer("forgotten", 1024)
+
+	runtime.GC()
+	time.Sleep(100 * time.Millisecond)
+	log.Println("live:", registry.Live(), "leaked:", registry.Leaked())
+}
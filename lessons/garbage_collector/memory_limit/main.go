@@ -0,0 +1,35 @@
+package main
+
+import (
+	"os"
+
+	"golang_course/lessons/garbage_collector/harness"
+)
+
+// implementation is in harness/harness.go
+
+var cache [][]byte
+
+// workload keeps ~16 MB alive and produces a lot of garbage
+func workload() {
+	for i := 0; i < 1<<16; i++ {
+		buffer := make([]byte, 16<<10)
+		if i%64 == 0 {
+			cache = append(cache, buffer)
+		}
+	}
+
+	cache = nil
+}
+
+func main() {
+	configs := harness.Matrix(
+		[]int{50, 100, 400, harness.GCOff},
+		[]int64{harness.NoLimit, 128 << 20, 512 << 20},
+	)
+
+	// GOGC=off without limit is the same as the ballast trick,
+	// but the heap can grow unbounded
+	results := harness.New().Run(workload, configs)
+	_ = harness.WriteTable(os.Stdout, results)
+}
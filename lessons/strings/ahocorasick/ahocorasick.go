@@ -0,0 +1,222 @@
+// Package ahocorasick finds all occurrences of many patterns
+// in one pass over the text using Aho-Corasick automaton
+package ahocorasick
+
+import (
+	"errors"
+	"io"
+)
+
+const (
+	rootState = 0
+	noState   = -1
+)
+
+type Match struct {
+	Pattern int   // index in the list of patterns
+	Offset  int64 // of the first byte of the match
+}
+
+// Matcher is a deterministic automaton, transitions for all
+// states and bytes are precomputed, so the text is scanned
+// with one table lookup per byte. Bytes are mapped to classes
+// (bytes that aren't in patterns share one class) to keep
+// the table small. Matcher is safe for concurrent use.
+type Matcher struct {
+	classes      [256]uint16
+	classesCount int
+	transitions  []int32 // states * classesCount
+
+	// patterns ending in the state and the next state by
+	// suffix links that has patterns (dictionary suffix link)
+	outputs    [][]int32
+	outputLink []int32
+
+	lengths []int
+}
+
+// New builds a case-sensitive matcher
+func New(patterns []string) (*Matcher, error) {
+	return build(patterns, false)
+}
+
+// NewCaseInsensitive builds a matcher that ignores case of ASCII
+// letters, other bytes (including UTF-8 sequences) are compared as is
+func NewCaseInsensitive(patterns []string) (*Matcher, error) {
+	return build(patterns, true)
+}
+
+// FindAll returns matches ordered by their ends
+func (m *Matcher) FindAll(data []byte) []Match {
+	var matches []Match
+	m.scan(rootState, 0, data, func(match Match) bool {
+		matches = append(matches, match)
+		return true
+	})
+
+	return matches
+}
+
+// Contains reports whether data contains any of patterns
+func (m *Matcher) Contains(data []byte) bool {
+	found := false
+	m.scan(rootState, 0, data, func(Match) bool {
+		found = true
+		return false
+	})
+
+	return found
+}
+
+// FindReader calls fn for matches in the stream until fn returns
+// false, matches split between reads are found as well
+func (m *Matcher) FindReader(reader io.Reader, fn func(Match) bool) error {
+	buffer := make([]byte, 32*1024)
+	state := int32(rootState)
+	offset := int64(0)
+	for {
+		count, err := reader.Read(buffer)
+		if count > 0 {
+			var stopped bool
+			state, stopped = m.scan(state, offset, buffer[:count], fn)
+			if stopped {
+				return nil
+			}
+
+			offset += int64(count)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// scan returns the last state and true if fn stopped scanning
+func (m *Matcher) scan(state int32, offset int64, data []byte, fn func(Match) bool) (int32, bool) {
+	for idx, value := range data {
+		state = m.transitions[int(state)*m.classesCount+int(m.classes[value])]
+
+		output := state
+		if len(m.outputs[output]) == 0 {
+			output = m.outputLink[output]
+		}
+
+		for output != noState {
+			for _, pattern := range m.outputs[output] {
+				end := offset + int64(idx) + 1
+				if !fn(Match{Pattern: int(pattern), Offset: end - int64(m.lengths[pattern])}) {
+					return state, true
+				}
+			}
+
+			output = m.outputLink[output]
+		}
+	}
+
+	return state, false
+}
+
+func build(patterns []string, caseInsensitive bool) (*Matcher, error) {
+	matcher := &Matcher{lengths: make([]int, len(patterns))}
+	fold := func(value byte) byte {
+		if caseInsensitive && 'A' <= value && value <= 'Z' {
+			return value + 'a' - 'A'
+		}
+
+		return value
+	}
+
+	// class 0 is for bytes that aren't in patterns
+	matcher.classesCount = 1
+	for idx, pattern := range patterns {
+		if len(pattern) == 0 {
+			return nil, errors.New("empty pattern")
+		}
+
+		matcher.lengths[idx] = len(pattern)
+		for _, value := range []byte(pattern) {
+			value = fold(value)
+			if matcher.classes[value] == 0 {
+				matcher.classes[value] = uint16(matcher.classesCount)
+				matcher.classesCount++
+			}
+		}
+	}
+
+	if caseInsensitive {
+		for value := 'A'; value <= 'Z'; value++ {
+			matcher.classes[value] = matcher.classes[fold(byte(value))]
+		}
+	}
+
+	// trie with noState for missing transitions
+	matcher.addState()
+	for idx, pattern := range patterns {
+		state := int32(rootState)
+		for _, value := range []byte(pattern) {
+			position := int(state)*matcher.classesCount + int(matcher.classes[value])
+			if matcher.transitions[position] == noState {
+				matcher.transitions[position] = matcher.addState()
+			}
+
+			state = matcher.transitions[position]
+		}
+
+		matcher.outputs[state] = append(matcher.outputs[state], int32(idx))
+	}
+
+	matcher.buildLinks()
+	return matcher, nil
+}
+
+func (m *Matcher) addState() int32 {
+	for class := 0; class < m.classesCount; class++ {
+		m.transitions = append(m.transitions, noState)
+	}
+
+	m.outputs = append(m.outputs, nil)
+	m.outputLink = append(m.outputLink, noState)
+	return int32(len(m.outputs) - 1)
+}
+
+// buildLinks replaces missing transitions with transitions of failure
+// states, states are processed in BFS order, so transitions of failure
+// states (they are closer to the root) are already complete
+func (m *Matcher) buildLinks() {
+	failures := make([]int32, len(m.outputs))
+	queue := []int32{rootState}
+	for len(queue) != 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for class := 0; class < m.classesCount; class++ {
+			position := int(state)*m.classesCount + class
+			next := m.transitions[position]
+			if next == noState {
+				if state == rootState {
+					m.transitions[position] = rootState
+				} else {
+					m.transitions[position] = m.transitions[int(failures[state])*m.classesCount+class]
+				}
+
+				continue
+			}
+
+			if state != rootState {
+				failures[next] = m.transitions[int(failures[state])*m.classesCount+class]
+			}
+
+			failure := failures[next]
+			if len(m.outputs[failure]) != 0 {
+				m.outputLink[next] = failure
+			} else {
+				m.outputLink[next] = m.outputLink[failure]
+			}
+
+			queue = append(queue, next)
+		}
+	}
+}
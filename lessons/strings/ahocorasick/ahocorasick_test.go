@@ -0,0 +1,209 @@
+package ahocorasick
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -bench=. -benchmem
+
+func TestFindAll(t *testing.T) {
+	matcher, err := New([]string{"he", "she", "his", "hers", "he"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Match{
+		{Pattern: 1, Offset: 1},
+		{Pattern: 0, Offset: 2},
+		{Pattern: 4, Offset: 2},
+		{Pattern: 3, Offset: 2},
+	}, matcher.FindAll([]byte("ushers")))
+
+	assert.Empty(t, matcher.FindAll([]byte("HERS")))
+	assert.True(t, matcher.Contains([]byte("this")))
+	assert.False(t, matcher.Contains([]byte("hi")))
+
+	_, err = New([]string{"a", ""})
+	assert.Error(t, err)
+}
+
+func TestCaseInsensitive(t *testing.T) {
+	matcher, err := NewCaseInsensitive([]string{"Error", "TIMEOUT", "отказ"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Match{
+		{Pattern: 0, Offset: 0},
+		{Pattern: 1, Offset: 7},
+		{Pattern: 2, Offset: 16},
+	}, matcher.FindAll([]byte("ERROR: timeout, отказ, ОТКАЗ")))
+}
+
+func TestAllBytes(t *testing.T) {
+	var pattern []byte
+	for value := 0; value < 256; value++ {
+		pattern = append(pattern, byte(value))
+	}
+
+	matcher, err := New([]string{string(pattern), "\xff\x00"})
+	require.NoError(t, err)
+
+	data := append(append([]byte{0xff}, pattern...), 0xff, 0x00)
+	assert.Equal(t, []Match{
+		{Pattern: 1, Offset: 0},
+		{Pattern: 0, Offset: 1},
+		{Pattern: 1, Offset: 257},
+	}, matcher.FindAll(data))
+}
+
+// naive returns matches found by strings.Index
+func naive(patterns []string, text string) []Match {
+	var matches []Match
+	for idx, pattern := range patterns {
+		for offset := 0; offset+len(pattern) <= len(text); offset++ {
+			if strings.HasPrefix(text[offset:], pattern) {
+				matches = append(matches, Match{Pattern: idx, Offset: int64(offset)})
+			}
+		}
+	}
+
+	return matches
+}
+
+func sortMatches(matches []Match) []Match {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Offset != matches[j].Offset {
+			return matches[i].Offset < matches[j].Offset
+		}
+
+		return matches[i].Pattern < matches[j].Pattern
+	})
+
+	return matches
+}
+
+func TestRandomTexts(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	randomString := func(length int) string {
+		var builder strings.Builder
+		for idx := 0; idx < length; idx++ {
+			builder.WriteByte("abc"[random.Intn(3)])
+		}
+
+		return builder.String()
+	}
+
+	for iteration := 0; iteration < 100; iteration++ {
+		patterns := make([]string, 1+random.Intn(10))
+		for idx := range patterns {
+			patterns[idx] = randomString(1 + random.Intn(5))
+		}
+
+		text := randomString(random.Intn(200))
+		matcher, err := New(patterns)
+		require.NoError(t, err)
+
+		expected := sortMatches(naive(patterns, text))
+		require.Equal(t, expected, sortMatches(matcher.FindAll([]byte(text))))
+
+		var streamed []Match
+		err = matcher.FindReader(iotest.OneByteReader(strings.NewReader(text)), func(match Match) bool {
+			streamed = append(streamed, match)
+			return true
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, expected, sortMatches(streamed))
+	}
+}
+
+func TestFindReaderStops(t *testing.T) {
+	matcher, err := New([]string{"a"})
+	require.NoError(t, err)
+
+	count := 0
+	err = matcher.FindReader(strings.NewReader("aaaa"), func(Match) bool {
+		count++
+		return count < 2
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	err = matcher.FindReader(iotest.ErrReader(fmt.Errorf("read error")), func(Match) bool { return true })
+	assert.EqualError(t, err, "read error")
+}
+
+var keywords, lines = func() ([]string, [][]byte) {
+	random := rand.New(rand.NewSource(1))
+	keywords := make([]string, 300)
+	for idx := range keywords {
+		keywords[idx] = fmt.Sprintf("keyword%d_%x", idx, random.Int63())
+	}
+
+	lines := make([][]byte, 1000)
+	for idx := range lines {
+		line := fmt.Sprintf("2024-01-01T00:00:00Z level=info request_id=%x message=%q", random.Int63(), strings.Repeat("text ", 10))
+		if idx%10 == 0 {
+			line += " " + keywords[random.Intn(len(keywords))]
+		}
+
+		lines[idx] = []byte(line)
+	}
+
+	return keywords, lines
+}()
+
+func BenchmarkStringsContains(b *testing.B) {
+	stringLines := make([]string, len(lines))
+	for idx, line := range lines {
+		stringLines[idx] = string(line)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		line := stringLines[i%len(stringLines)]
+		for _, keyword := range keywords {
+			if strings.Contains(line, keyword) {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkBytesContains(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		line := lines[i%len(lines)]
+		for _, keyword := range keywords {
+			if bytes.Contains(line, []byte(keyword)) {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkMatcherContains(b *testing.B) {
+	matcher, _ := New(keywords)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = matcher.Contains(lines[i%len(lines)])
+	}
+}
+
+func BenchmarkMatcherFindAll(b *testing.B) {
+	matcher, _ := New(keywords)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = matcher.FindAll(lines[i%len(lines)])
+	}
+}
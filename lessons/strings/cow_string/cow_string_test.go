@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dataPointer(s *COWString) *byte {
+	return unsafe.SliceData(s.data)
+}
+
+func TestCopyAndRelease(t *testing.T) {
+	str := NewString([]byte("hello")...)
+	pointer := dataPointer(&str)
+
+	copied := str.Copy()
+	require.NoError(t, copied.Set(0, 'j'))
+	assert.Equal(t, "hello", str.ToString())
+	assert.Equal(t, "jello", copied.ToString())
+	assert.NotSame(t, pointer, dataPointer(&copied))
+
+	// copied took its own array, so str is the only owner again
+	require.NoError(t, str.Set(0, 'c'))
+	assert.Equal(t, "cello", str.ToString())
+	assert.Same(t, pointer, dataPointer(&str))
+}
+
+func TestLastOwnerMutatesInPlace(t *testing.T) {
+	str := NewString([]byte("hello")...)
+	pointer := dataPointer(&str)
+
+	copied := str.Copy()
+	copied.Release()
+	assert.Equal(t, 0, copied.Length())
+
+	require.NoError(t, str.Set(4, 'O'))
+	assert.Equal(t, "hellO", str.ToString())
+	assert.Same(t, pointer, dataPointer(&str))
+}
+
+func TestSubstring(t *testing.T) {
+	str := NewString([]byte("hello world")...)
+	world, err := str.Substring(6, 11)
+	require.NoError(t, err)
+
+	assert.Equal(t, "world", world.ToString())
+	assert.Same(t, &str.data[6], dataPointer(&world))
+
+	world.Append('!')
+	assert.Equal(t, "world!", world.ToString())
+	assert.Equal(t, "hello world", str.ToString())
+
+	_, err = str.Substring(5, 12)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = str.Substring(3, 2)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestSubstringCantAppendOverSource(t *testing.T) {
+	data := make([]byte, 5, 16)
+	copy(data, "hello")
+
+	str := NewString(data...)
+	hell, err := str.Substring(0, 4)
+	require.NoError(t, err)
+
+	str.Release()
+	hell.Append('p')
+	assert.Equal(t, "hellp", hell.ToString())
+	assert.Equal(t, byte('o'), data[4])
+}
+
+func TestBoundsChecking(t *testing.T) {
+	str := NewString([]byte("abc")...)
+
+	value, err := str.Get(2)
+	require.NoError(t, err)
+	assert.Equal(t, byte('c'), value)
+
+	_, err = str.Get(3)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = str.Get(-1)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	assert.ErrorIs(t, str.Set(3, 'd'), ErrOutOfRange)
+}
+
+func TestStringIsZeroCopyAndStable(t *testing.T) {
+	str := NewString([]byte("hello")...)
+	pointer := dataPointer(&str)
+
+	view := str.String()
+	assert.Same(t, pointer, unsafe.StringData(view))
+
+	require.NoError(t, str.Set(0, 'j'))
+	assert.Equal(t, "hello", view)
+	assert.Equal(t, "jello", str.ToString())
+}
+
+func TestZeroValue(t *testing.T) {
+	var str COWString
+	assert.Equal(t, "", str.String())
+
+	str.Append('a')
+	assert.Equal(t, "a", str.ToString())
+
+	str.Release()
+	str.Release()
+	assert.Equal(t, 0, str.Length())
+}
@@ -1,15 +1,33 @@
 package main
 
-// Copy-On-Write
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+var ErrOutOfRange = errors.New("index out of range")
+
+// owners of one backing array
+type owners struct {
+	count int
+
+	// backing array is referenced by strings returned
+	// from String, so it can't be mutated anymore
+	pinned bool
+}
+
+// Copy-On-Write (not safe for concurrent use)
 type COWString struct {
-	data []byte
-	refs *int
+	data   []byte
+	owners *owners
 }
 
+// NewString takes ownership of values
 func NewString(values ...byte) COWString {
 	return COWString{
-		data: values,
-		refs: new(int),
+		data:   values,
+		owners: &owners{count: 1},
 	}
 }
 
@@ -21,43 +39,111 @@ func (s *COWString) Capacity() int {
 	return cap(s.data)
 }
 
+// ToString returns a copy of data
 func (s *COWString) ToString() string {
 	return string(s.data) // copying...
 }
 
-func (s *COWString) Get(idx int) byte {
-	// without bound checking
-	return s.data[idx]
+// String returns data without copying, the backing array is pinned,
+// so the next mutation of any owner copies data instead of changing it
+func (s *COWString) String() string {
+	if len(s.data) == 0 {
+		return ""
+	}
+
+	s.owners.pinned = true
+	return unsafe.String(unsafe.SliceData(s.data), len(s.data))
 }
 
-func (s *COWString) Set(idx int, value byte) {
-	// without bound checking
+func (s *COWString) Get(idx int) (byte, error) {
+	if idx < 0 || idx >= len(s.data) {
+		return 0, ErrOutOfRange
+	}
 
-	if *s.refs > 0 {
-		s.data = append([]byte(nil), s.data...)
+	return s.data[idx], nil
+}
+
+func (s *COWString) Set(idx int, value byte) error {
+	if idx < 0 || idx >= len(s.data) {
+		return ErrOutOfRange
 	}
 
+	s.detach(len(s.data))
 	s.data[idx] = value
+	return nil
 }
 
+// Copy shares the backing array with the new owner
 func (s *COWString) Copy() COWString {
-	(*s.refs)++
+	if s.owners == nil {
+		return COWString{}
+	}
+
+	s.owners.count++
 	return COWString{
-		data: s.data,
-		refs: s.refs,
+		data:   s.data,
+		owners: s.owners,
 	}
 }
 
-func (s *COWString) Append(values ...byte) {
-	if *s.refs > 0 {
-		s.data = append([]byte(nil), s.data...)
+// Substring returns a view of [start, end) sharing the backing array,
+// capacity of the view is limited to prevent appending over the source
+func (s *COWString) Substring(start, end int) (COWString, error) {
+	if start < 0 || end > len(s.data) || start > end {
+		return COWString{}, ErrOutOfRange
 	}
 
+	substring := s.Copy()
+	substring.data = substring.data[start:end:end]
+	return substring, nil
+}
+
+func (s *COWString) Append(values ...byte) {
+	s.detach(len(s.data) + len(values))
 	s.data = append(s.data, values...)
 }
 
+// Release gives up ownership, the last owner can mutate
+// the backing array without copying, the string becomes empty
+func (s *COWString) Release() {
+	if s.owners != nil {
+		s.owners.count--
+	}
+
+	s.data = nil
+	s.owners = nil
+}
+
+// detach makes s the only owner of its backing array
+// copying data if it's shared with other owners
+func (s *COWString) detach(capacity int) {
+	if s.owners != nil && s.owners.count == 1 && !s.owners.pinned {
+		return
+	}
+
+	if s.owners != nil {
+		s.owners.count--
+	}
+
+	data := make([]byte, len(s.data), max(capacity, cap(s.data)))
+	copy(data, s.data)
+
+	s.data = data
+	s.owners = &owners{count: 1}
+}
+
 func main() {
 	str := NewString([]byte("Hello world")...)
-	temp := str
-	_ = temp
+	hello, _ := str.Substring(0, 5)
+
+	temp := str.Copy()
+	_ = temp.Set(0, 'h') // copied, str is shared
+	temp.Release()
+
+	fmt.Println(str.String(), hello.String())
+	hello.Release()
+
+	_ = str.Set(0, 'J') // copied, str was pinned by String
+	str.Append('!')
+	fmt.Println(str.String())
 }
@@ -1,6 +1,14 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang_course/lessons/strings/transcoding"
+)
+
+// validation and transcoding are in transcoding/
 
 func main() {
 	// incorrect code points
@@ -8,4 +16,11 @@ func main() {
 	str := string(data)
 
 	fmt.Println(str)
+
+	// invalid bytes can be found and repaired explicitly
+	invalid, _ := transcoding.Validate(strings.NewReader("text \xff\xfe"))
+	fmt.Println(invalid)
+
+	repaired, _ := io.ReadAll(transcoding.NewRepairReader(strings.NewReader("text \xff\xfe"), transcoding.Escape))
+	fmt.Println(string(repaired))
 }
@@ -0,0 +1,213 @@
+// Package frequency counts runes, letters, words and n-grams of a text
+package frequency
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Frequencies of letters, words and n-grams are counted after NFC
+// normalization and case folding, so "É", "é" and "é" are the same
+// letter. Letter is a letter rune with following combining marks
+// (close to grapheme cluster for letters without precomposed form).
+type Frequencies struct {
+	Runes   map[rune]int
+	Letters map[string]int
+	Words   map[string]int
+	NGrams  map[string]int // of letters inside of words
+
+	TotalRunes   int
+	TotalLetters int
+	TotalWords   int
+	TotalNGrams  int
+}
+
+type Entry[K cmp.Ordered] struct {
+	Value K
+	Count int
+}
+
+// Analyze reads the text as a stream, n-grams are
+// counted for ngramSize > 0 (for example 2 for bigrams)
+func Analyze(reader io.Reader, ngramSize int) (*Frequencies, error) {
+	if ngramSize < 0 {
+		return nil, errors.New("incorrect n-gram size")
+	}
+
+	analyzer := analyzer{
+		frequencies: &Frequencies{
+			Runes:   make(map[rune]int),
+			Letters: make(map[string]int),
+			Words:   make(map[string]int),
+			NGrams:  make(map[string]int),
+		},
+		caser:     cases.Fold(),
+		ngramSize: ngramSize,
+	}
+
+	normalized := bufio.NewReader(transform.NewReader(reader, norm.NFC))
+	for {
+		symbol, _, err := normalized.ReadRune()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		analyzer.frequencies.Runes[symbol]++
+		analyzer.frequencies.TotalRunes++
+
+		if isWordRune(symbol) {
+			analyzer.word.WriteRune(symbol)
+		} else {
+			analyzer.flushWord()
+		}
+	}
+
+	analyzer.flushWord()
+	return analyzer.frequencies, nil
+}
+
+// SortedRunes returns runes sorted by count (descending) and value
+func (f *Frequencies) SortedRunes() []Entry[rune] {
+	return Sorted(f.Runes)
+}
+
+func (f *Frequencies) SortedLetters() []Entry[string] {
+	return Sorted(f.Letters)
+}
+
+func (f *Frequencies) SortedWords() []Entry[string] {
+	return Sorted(f.Words)
+}
+
+func (f *Frequencies) SortedNGrams() []Entry[string] {
+	return Sorted(f.NGrams)
+}
+
+// Sorted returns entries sorted by count (descending) and value
+func Sorted[K cmp.Ordered](counts map[K]int) []Entry[K] {
+	entries := make([]Entry[K], 0, len(counts))
+	for value, count := range counts {
+		entries = append(entries, Entry[K]{Value: value, Count: count})
+	}
+
+	slices.SortFunc(entries, func(left, right Entry[K]) int {
+		if left.Count != right.Count {
+			return cmp.Compare(right.Count, left.Count)
+		}
+
+		return cmp.Compare(left.Value, right.Value)
+	})
+
+	return entries
+}
+
+// ChiSquareDistance compares distributions of counts:
+// sum of (p - q)² / (p + q) by relative frequencies p and q,
+// it's 0 for the same distributions and 2 for disjoint ones
+func ChiSquareDistance[K comparable](left, right map[K]int) float64 {
+	leftTotal, rightTotal := total(left), total(right)
+	if leftTotal == 0 || rightTotal == 0 {
+		if leftTotal == rightTotal {
+			return 0
+		}
+
+		return 2
+	}
+
+	var distance float64
+	for value, count := range left {
+		p := float64(count) / float64(leftTotal)
+		q := float64(right[value]) / float64(rightTotal)
+		distance += (p - q) * (p - q) / (p + q)
+	}
+
+	for value, count := range right {
+		if _, found := left[value]; !found && count != 0 {
+			distance += float64(count) / float64(rightTotal)
+		}
+	}
+
+	// rounding errors for the same distributions
+	return math.Max(distance, 0)
+}
+
+func total[K comparable](counts map[K]int) int {
+	var result int
+	for _, count := range counts {
+		result += count
+	}
+
+	return result
+}
+
+type analyzer struct {
+	frequencies *Frequencies
+	caser       cases.Caser
+	ngramSize   int
+
+	word    strings.Builder
+	letters []string // letters of the current word
+}
+
+func (a *analyzer) flushWord() {
+	if a.word.Len() == 0 {
+		return
+	}
+
+	// folding is done for the whole word, because
+	// it can change the number of runes (ß -> ss)
+	word := a.caser.String(a.word.String())
+	a.word.Reset()
+
+	a.frequencies.Words[word]++
+	a.frequencies.TotalWords++
+
+	a.letters = a.letters[:0]
+	start := -1
+	for idx, symbol := range word {
+		if start >= 0 && unicode.Is(unicode.Mn, symbol) {
+			// combining mark belongs to the previous letter
+			continue
+		}
+
+		if start >= 0 {
+			a.letters = append(a.letters, word[start:idx])
+		}
+
+		start = idx
+	}
+
+	a.letters = append(a.letters, word[start:])
+
+	for _, letter := range a.letters {
+		a.frequencies.Letters[letter]++
+		a.frequencies.TotalLetters++
+	}
+
+	if a.ngramSize == 0 {
+		return
+	}
+
+	for start := 0; start+a.ngramSize <= len(a.letters); start++ {
+		ngram := strings.Join(a.letters[start:start+a.ngramSize], "")
+		a.frequencies.NGrams[ngram]++
+		a.frequencies.TotalNGrams++
+	}
+}
+
+// words consist of letters with combining marks
+func isWordRune(symbol rune) bool {
+	return unicode.IsLetter(symbol) || unicode.Is(unicode.Mn, symbol)
+}
@@ -0,0 +1,79 @@
+package frequency
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyze(t *testing.T) {
+	// "é" is decomposed in the second word
+	text := "Été, ÉTÉ! été Straße 42"
+	frequencies, err := Analyze(strings.NewReader(text), 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, []Entry[string]{
+		{Value: "été", Count: 3},
+		{Value: "strasse", Count: 1},
+	}, frequencies.SortedWords())
+
+	assert.Equal(t, []Entry[string]{
+		{Value: "é", Count: 6},
+		{Value: "t", Count: 4},
+		{Value: "s", Count: 3},
+		{Value: "a", Count: 1},
+		{Value: "e", Count: 1},
+		{Value: "r", Count: 1},
+	}, frequencies.SortedLetters())
+
+	assert.Equal(t, 3, frequencies.NGrams["ét"])
+	assert.Equal(t, 1, frequencies.NGrams["ss"])
+	assert.Equal(t, 4, frequencies.TotalWords)
+	assert.Equal(t, 16, frequencies.TotalLetters)
+	assert.Equal(t, 12, frequencies.TotalNGrams)
+
+	assert.Equal(t, 3, frequencies.Runes['É'])
+	assert.Equal(t, 3, frequencies.Runes['é'])
+	assert.Equal(t, 1, frequencies.Runes['4'])
+	assert.Equal(t, len([]rune(text)), frequencies.TotalRunes+2) // 2 runes are composed
+}
+
+func TestAnalyzeWithoutNGrams(t *testing.T) {
+	frequencies, err := Analyze(strings.NewReader("aabaacdb"), 0)
+	require.NoError(t, err)
+
+	assert.Empty(t, frequencies.NGrams)
+	assert.Equal(t, []Entry[rune]{
+		{Value: 'a', Count: 4},
+		{Value: 'b', Count: 2},
+		{Value: 'c', Count: 1},
+		{Value: 'd', Count: 1},
+	}, frequencies.SortedRunes())
+
+	_, err = Analyze(strings.NewReader(""), -1)
+	assert.Error(t, err)
+}
+
+func TestChiSquareDistance(t *testing.T) {
+	english := "the quick brown fox jumps over the lazy dog and then the dog sleeps"
+	englishOther := "then they went home where the cat was sleeping on the sofa"
+	russian := "съешь же ещё этих мягких французских булок да выпей чаю"
+
+	analyze := func(text string) map[string]int {
+		frequencies, err := Analyze(strings.NewReader(text), 1)
+		require.NoError(t, err)
+		return frequencies.NGrams
+	}
+
+	assert.Zero(t, ChiSquareDistance(analyze(english), analyze(english)))
+	assert.InDelta(t, 2.0, ChiSquareDistance(analyze(english), analyze(russian)), 1e-9)
+
+	close := ChiSquareDistance(analyze(english), analyze(englishOther))
+	assert.Greater(t, close, 0.0)
+	assert.Less(t, close, 1.0)
+
+	assert.Zero(t, ChiSquareDistance(map[string]int{}, map[string]int{}))
+	assert.InDelta(t, 2.0/3, ChiSquareDistance(map[rune]int{'a': 1}, map[rune]int{'a': 1, 'b': 1}), 1e-9)
+}
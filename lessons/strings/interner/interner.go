@@ -0,0 +1,182 @@
+// Package interner keeps one shared copy of each distinct string,
+// copies are cloned, so they don't pin big source strings
+package interner
+
+import (
+	"errors"
+	"hash/maphash"
+	"strings"
+	"sync"
+)
+
+// small interners have fewer shards, so each
+// shard keeps several values and doesn't thrash
+const (
+	maxShards        = 16
+	minShardCapacity = 8
+)
+
+type Stats struct {
+	Entries     int
+	Hits        int
+	Misses      int
+	Evictions   int
+	BytesStored int // sum of lengths of stored strings
+
+	// bytes that would be allocated by
+	// separate copies of interned strings
+	BytesSaved int
+}
+
+// node of LRU list, the front is the most recently used
+type node struct {
+	value      string
+	prev, next *node
+}
+
+type shard struct {
+	mutex    sync.Mutex
+	nodes    map[string]*node
+	head     *node
+	tail     *node
+	capacity int
+	stats    Stats
+}
+
+// Interner is safe for concurrent use, values are distributed
+// between shards by hash, each shard evicts its least recently
+// used values when it's full
+type Interner struct {
+	seed   maphash.Seed
+	shards []shard
+}
+
+// New creates an interner that keeps at most capacity values, capacity
+// is split between shards, so values with unlucky hashes can be evicted
+// while there are fewer values in total
+func New(capacity int) (*Interner, error) {
+	if capacity <= 0 {
+		return nil, errors.New("incorrect capacity")
+	}
+
+	shards := make([]shard, min(maxShards, max(1, capacity/minShardCapacity)))
+	for idx := range shards {
+		shards[idx].nodes = make(map[string]*node)
+		shards[idx].capacity = capacity / len(shards)
+		if idx < capacity%len(shards) {
+			shards[idx].capacity++
+		}
+	}
+
+	return &Interner{
+		seed:   maphash.MakeSeed(),
+		shards: shards,
+	}, nil
+}
+
+// Intern returns the shared copy of value
+func (i *Interner) Intern(value string) string {
+	shard := &i.shards[maphash.String(i.seed, value)%uint64(len(i.shards))]
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	if result, found := shard.lookup(value); found {
+		return result
+	}
+
+	return shard.insert(strings.Clone(value))
+}
+
+// InternBytes doesn't allocate if value is already interned
+func (i *Interner) InternBytes(value []byte) string {
+	shard := &i.shards[maphash.Bytes(i.seed, value)%uint64(len(i.shards))]
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	// conversion in map index doesn't allocate
+	if result, found := shard.lookup(string(value)); found {
+		return result
+	}
+
+	return shard.insert(string(value))
+}
+
+func (i *Interner) Stats() Stats {
+	var stats Stats
+	for idx := range i.shards {
+		shard := &i.shards[idx]
+		shard.mutex.Lock()
+		stats.Entries += shard.stats.Entries
+		stats.Hits += shard.stats.Hits
+		stats.Misses += shard.stats.Misses
+		stats.Evictions += shard.stats.Evictions
+		stats.BytesStored += shard.stats.BytesStored
+		stats.BytesSaved += shard.stats.BytesSaved
+		shard.mutex.Unlock()
+	}
+
+	return stats
+}
+
+func (s *shard) lookup(value string) (string, bool) {
+	node, found := s.nodes[value]
+	if !found {
+		return "", false
+	}
+
+	s.stats.Hits++
+	s.stats.BytesSaved += len(node.value)
+	s.unlink(node)
+	s.pushFront(node)
+	return node.value, true
+}
+
+func (s *shard) insert(value string) string {
+	s.stats.Misses++
+	if len(s.nodes) == s.capacity {
+		oldest := s.tail
+		s.unlink(oldest)
+		delete(s.nodes, oldest.value)
+
+		s.stats.Evictions++
+		s.stats.Entries--
+		s.stats.BytesStored -= len(oldest.value)
+	}
+
+	node := &node{value: value}
+	s.nodes[value] = node
+	s.pushFront(node)
+
+	s.stats.Entries++
+	s.stats.BytesStored += len(value)
+	return value
+}
+
+func (s *shard) pushFront(node *node) {
+	node.prev = nil
+	node.next = s.head
+	if s.head != nil {
+		s.head.prev = node
+	} else {
+		s.tail = node
+	}
+
+	s.head = node
+}
+
+func (s *shard) unlink(node *node) {
+	if node.prev != nil {
+		node.prev.next = node.next
+	} else {
+		s.head = node.next
+	}
+
+	if node.next != nil {
+		node.next.prev = node.prev
+	} else {
+		s.tail = node.prev
+	}
+
+	node.prev = nil
+	node.next = nil
+}
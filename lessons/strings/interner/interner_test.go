@@ -0,0 +1,154 @@
+package interner
+
+import (
+	"hash/maphash"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -bench=. -benchmem
+
+func TestIntern(t *testing.T) {
+	interner, err := New(100)
+	require.NoError(t, err)
+
+	line := "2024-01-01 level=error service=payments " + strings.Repeat("x", 1024)
+	first := interner.Intern(line[11:22])
+	second := interner.InternBytes([]byte("level=error"))
+	third := interner.Intern(strings.Clone("level=error"))
+
+	assert.Equal(t, "level=error", first)
+	assert.Same(t, unsafe.StringData(first), unsafe.StringData(second))
+	assert.Same(t, unsafe.StringData(first), unsafe.StringData(third))
+
+	// cloned off the source line
+	assert.NotSame(t, unsafe.StringData(line[11:]), unsafe.StringData(first))
+
+	assert.Equal(t, Stats{
+		Entries:     1,
+		Hits:        2,
+		Misses:      1,
+		BytesStored: 11,
+		BytesSaved:  22,
+	}, interner.Stats())
+
+	_, err = New(0)
+	assert.Error(t, err)
+}
+
+// valuesOfShard returns values that are stored in the same shard
+func valuesOfShard(interner *Interner, count int) []string {
+	var values []string
+	for idx := 0; len(values) < count; idx++ {
+		value := strconv.Itoa(idx)
+		if maphash.String(interner.seed, value)%uint64(len(interner.shards)) == 0 {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func TestEviction(t *testing.T) {
+	interner, err := New(2 * minShardCapacity * maxShards)
+	require.NoError(t, err)
+	assert.Len(t, interner.shards, maxShards)
+
+	// one shard keeps 2 values
+	interner, err = New(2)
+	require.NoError(t, err)
+	assert.Len(t, interner.shards, 1)
+
+	values := valuesOfShard(interner, 3)
+	first := interner.Intern(values[0])
+	interner.Intern(values[1])
+
+	// values[1] is the least recently used now
+	assert.Same(t, unsafe.StringData(first), unsafe.StringData(interner.Intern(values[0])))
+	interner.Intern(values[2])
+
+	stats := interner.Stats()
+	assert.Equal(t, 2, stats.Entries)
+	assert.Equal(t, 1, stats.Evictions)
+	assert.Equal(t, len(values[0])+len(values[2]), stats.BytesStored)
+
+	assert.Same(t, unsafe.StringData(first), unsafe.StringData(interner.Intern(values[0])))
+	assert.Equal(t, 1, interner.Stats().Evictions)
+
+	interner.Intern(values[1])
+	assert.Equal(t, 2, interner.Stats().Evictions)
+}
+
+func TestInternBytesWithoutAllocations(t *testing.T) {
+	interner, err := New(100)
+	require.NoError(t, err)
+
+	value := []byte("service=payments")
+	interner.InternBytes(value)
+
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		_ = interner.InternBytes(value)
+	}))
+}
+
+func TestConcurrentIntern(t *testing.T) {
+	interner, err := New(64)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for goroutine := 0; goroutine < 8; goroutine++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := 0; idx < 1000; idx++ {
+				value := strconv.Itoa(idx % 100)
+				assert.Equal(t, value, interner.Intern(value))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	stats := interner.Stats()
+	assert.Equal(t, 8000, stats.Hits+stats.Misses)
+	assert.LessOrEqual(t, stats.Entries, 64)
+}
+
+func TestCapacityIsGlobalBound(t *testing.T) {
+	for _, capacity := range []int{1, 2, 7, 8, 15, 16, 33, 127, 129, 1000} {
+		interner, err := New(capacity)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(interner.shards), maxShards)
+
+		for idx := 0; idx < 4*capacity+100; idx++ {
+			interner.Intern(strconv.Itoa(idx))
+			require.LessOrEqual(t, interner.Stats().Entries, capacity)
+		}
+
+		// every shard is full
+		assert.Equal(t, capacity, interner.Stats().Entries)
+	}
+}
+
+func BenchmarkIntern(b *testing.B) {
+	values := make([][]byte, 1<<12)
+	interner, _ := New(len(values))
+	for idx := range values {
+		values[idx] = []byte("field=" + strconv.Itoa(idx))
+	}
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		idx := 0
+		for pb.Next() {
+			_ = interner.InternBytes(values[idx%len(values)])
+			idx++
+		}
+	})
+}
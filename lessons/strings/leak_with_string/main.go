@@ -1,5 +1,9 @@
 package main
 
+import "golang_course/lessons/strings/interner"
+
+var fields, _ = interner.New(1 << 20)
+
 func FindData(filename string) string {
 	var data string
 	// reading data from file..
@@ -13,7 +17,15 @@ func FindData(filename string) string {
 	return ""
 }
 
+// FindDataWithoutLeak returns a copy shared
+// by all equal values (see interner/)
+func FindDataWithoutLeak(filename string) string {
+	return fields.Intern(FindData(filename))
+}
+
 func main() {
 	_ = FindData("data.txt")
 	// potentially high memory consumption
+
+	_ = FindDataWithoutLeak("data.txt")
 }
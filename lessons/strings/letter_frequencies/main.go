@@ -1,22 +1,33 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
 
-func CalculateFrequencies(str string) {
-	frequencies := [26]int{}
-	for _, letter := range str {
-		letterIdx := letter - 'a'
-		frequencies[letterIdx]++
-	}
+	"golang_course/lessons/strings/frequency"
+)
+
+// implementation is in frequency/frequency.go, the previous
+// version indexed [26]int by letter - 'a' and panicked on
+// uppercase, digits and non-ASCII letters
 
-	for letterIdx, frequency := range frequencies {
-		if frequency != 0 {
-			letter := 'a' + letterIdx
-			fmt.Printf("%c = %d\n", letter, frequency)
-		}
+func CalculateFrequencies(str string) ([]frequency.Entry[string], error) {
+	frequencies, err := frequency.Analyze(strings.NewReader(str), 0)
+	if err != nil {
+		return nil, err
 	}
+
+	return frequencies.SortedLetters(), nil
 }
 
 func main() {
-	CalculateFrequencies("aabaacdb")
+	letters, err := CalculateFrequencies("aabaacdb Ab, ЁЖ ёж 42")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, letter := range letters {
+		fmt.Printf("%s = %d\n", letter.Value, letter.Count)
+	}
 }
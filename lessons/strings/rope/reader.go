@@ -0,0 +1,131 @@
+package rope
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// Reader reads bytes and runes of a rope without flattening
+// it, runes can be split between leaves after edits
+type Reader struct {
+	stack []*node // right subtrees to visit
+	leaf  string
+
+	// bytes read ahead to decode a rune split between
+	// leaves, but not returned (for invalid sequences)
+	pending       [utf8.UTFMax]byte
+	pendingLength int
+}
+
+func (r Rope) Reader() *Reader {
+	reader := &Reader{}
+	reader.pushLeft(r.root)
+	return reader
+}
+
+func (r *Reader) Read(buffer []byte) (int, error) {
+	if len(buffer) == 0 {
+		return 0, nil
+	}
+
+	read := 0
+	for read < len(buffer) && r.pendingLength != 0 {
+		buffer[read] = r.popPending()
+		read++
+	}
+
+	for read < len(buffer) && r.nextLeaf() {
+		copied := copy(buffer[read:], r.leaf)
+		r.leaf = r.leaf[copied:]
+		read += copied
+	}
+
+	if read == 0 {
+		return 0, io.EOF
+	}
+
+	return read, nil
+}
+
+func (r *Reader) ReadByte() (byte, error) {
+	if r.pendingLength != 0 {
+		return r.popPending(), nil
+	}
+
+	if !r.nextLeaf() {
+		return 0, io.EOF
+	}
+
+	value := r.leaf[0]
+	r.leaf = r.leaf[1:]
+	return value, nil
+}
+
+func (r *Reader) ReadRune() (rune, int, error) {
+	if r.pendingLength == 0 && r.nextLeaf() && utf8.FullRuneInString(r.leaf) {
+		value, size := utf8.DecodeRuneInString(r.leaf)
+		r.leaf = r.leaf[size:]
+		return value, size, nil
+	}
+
+	// rune is split between leaves
+	var buffer [utf8.UTFMax]byte
+	length := 0
+	for length < utf8.UTFMax && !utf8.FullRune(buffer[:length]) {
+		value, err := r.ReadByte()
+		if err != nil {
+			break
+		}
+
+		buffer[length] = value
+		length++
+	}
+
+	if length == 0 {
+		return 0, 0, io.EOF
+	}
+
+	value, size := utf8.DecodeRune(buffer[:length])
+	r.unread(buffer[size:length])
+	return value, size, nil
+}
+
+// nextLeaf skips empty leaves and returns false at the end
+func (r *Reader) nextLeaf() bool {
+	for len(r.leaf) == 0 {
+		if len(r.stack) == 0 {
+			return false
+		}
+
+		top := r.stack[len(r.stack)-1]
+		r.stack = r.stack[:len(r.stack)-1]
+		r.pushLeft(top)
+	}
+
+	return true
+}
+
+// pushLeft descends to the leftmost leaf of n
+func (r *Reader) pushLeft(n *node) {
+	for n != nil && !n.isLeaf() {
+		r.stack = append(r.stack, n.right)
+		n = n.left
+	}
+
+	if n != nil {
+		r.leaf = n.text
+	}
+}
+
+func (r *Reader) popPending() byte {
+	value := r.pending[0]
+	copy(r.pending[:], r.pending[1:r.pendingLength])
+	r.pendingLength--
+	return value
+}
+
+func (r *Reader) unread(values []byte) {
+	copy(r.pending[len(values):], r.pending[:r.pendingLength])
+	copy(r.pending[:], values)
+	r.pendingLength += len(values)
+}
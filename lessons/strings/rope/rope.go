@@ -0,0 +1,246 @@
+// Package rope implements an immutable rope for editing of large texts,
+// edits share unchanged parts, so they don't copy the whole text
+package rope
+
+import (
+	"errors"
+	"math/bits"
+	"strings"
+)
+
+// leaves up to this size are merged on concatenation
+const maxLeafSize = 512
+
+var ErrOutOfRange = errors.New("index out of range")
+
+type node struct {
+	left   *node
+	right  *node
+	text   string // only in leaves
+	length int
+	depth  int // 0 for leaves
+	leaves int
+
+	// flattened text of internal node, created lazily by String
+	flat      string
+	flattened bool
+}
+
+func (n *node) isLeaf() bool {
+	return n.left == nil
+}
+
+// Rope is a value type, all operations return new ropes and don't
+// change the source one. Indexes are in bytes like for strings. Rope
+// is not safe for concurrent use, because String caches its result.
+type Rope struct {
+	root *node
+}
+
+func New(text string) Rope {
+	return Rope{root: newLeaf(text)}
+}
+
+func (r Rope) Len() int {
+	if r.root == nil {
+		return 0
+	}
+
+	return r.root.length
+}
+
+func (r Rope) Concat(other Rope) Rope {
+	return Rope{root: concat(r.root, other.root)}
+}
+
+func (r Rope) Insert(at int, text string) (Rope, error) {
+	if at < 0 || at > r.Len() {
+		return Rope{}, ErrOutOfRange
+	}
+
+	left, right := split(r.root, at)
+	return Rope{root: concat(concat(left, newLeaf(text)), right)}, nil
+}
+
+// Delete removes [start, end)
+func (r Rope) Delete(start, end int) (Rope, error) {
+	if start < 0 || end > r.Len() || start > end {
+		return Rope{}, ErrOutOfRange
+	}
+
+	left, rest := split(r.root, start)
+	_, right := split(rest, end-start)
+	return Rope{root: concat(left, right)}, nil
+}
+
+// Slice returns [start, end) sharing leaves with r
+func (r Rope) Slice(start, end int) (Rope, error) {
+	if start < 0 || end > r.Len() || start > end {
+		return Rope{}, ErrOutOfRange
+	}
+
+	_, rest := split(r.root, start)
+	middle, _ := split(rest, end-start)
+	return Rope{root: middle}, nil
+}
+
+// Index returns the index of the first instance of substr or -1
+func (r Rope) Index(substr string) int {
+	if len(substr) == 0 {
+		return 0
+	}
+
+	// tail of previous leaves to find matches crossing boundaries
+	var carry string
+	offset := 0
+
+	found := -1
+	eachLeaf(r.root, func(text string) bool {
+		boundary := carry + text[:min(len(text), len(substr)-1)]
+		if idx := strings.Index(boundary, substr); idx >= 0 {
+			found = offset - len(carry) + idx
+			return false
+		}
+
+		if idx := strings.Index(text, substr); idx >= 0 {
+			found = offset + idx
+			return false
+		}
+
+		if len(text) >= len(substr)-1 {
+			carry = text[len(text)-(len(substr)-1):]
+		} else {
+			carry += text
+			carry = carry[max(0, len(carry)-(len(substr)-1)):]
+		}
+
+		offset += len(text)
+		return true
+	})
+
+	return found
+}
+
+// String flattens the rope once, the result is cached
+func (r Rope) String() string {
+	if r.root == nil {
+		return ""
+	}
+
+	if r.root.isLeaf() {
+		return r.root.text
+	}
+
+	if !r.root.flattened {
+		var builder strings.Builder
+		builder.Grow(r.root.length)
+		eachLeaf(r.root, func(text string) bool {
+			builder.WriteString(text)
+			return true
+		})
+
+		r.root.flat = builder.String()
+		r.root.flattened = true
+	}
+
+	return r.root.flat
+}
+
+func newLeaf(text string) *node {
+	if len(text) == 0 {
+		return nil
+	}
+
+	return &node{text: text, length: len(text), leaves: 1}
+}
+
+func join(left, right *node) *node {
+	return &node{
+		left:   left,
+		right:  right,
+		length: left.length + right.length,
+		depth:  max(left.depth, right.depth) + 1,
+		leaves: left.leaves + right.leaves,
+	}
+}
+
+// concat merges small leaves and rebalances the tree if
+// it's much deeper than a balanced tree with the same leaves
+func concat(left, right *node) *node {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.isLeaf() && right.isLeaf() && left.length+right.length <= maxLeafSize:
+		return newLeaf(left.text + right.text)
+	case !left.isLeaf() && left.right.isLeaf() && right.isLeaf() && left.right.length+right.length <= maxLeafSize:
+		// common case of appending small pieces
+		return join(left.left, newLeaf(left.right.text+right.text))
+	}
+
+	result := join(left, right)
+	if result.depth > 2*bits.Len(uint(result.leaves))+2 {
+		return rebalance(result)
+	}
+
+	return result
+}
+
+// split returns [0, at) and [at, length) of n
+func split(n *node, at int) (*node, *node) {
+	switch {
+	case n == nil:
+		return nil, nil
+	case at == 0:
+		return nil, n
+	case at == n.length:
+		return n, nil
+	case n.isLeaf():
+		return newLeaf(n.text[:at]), newLeaf(n.text[at:])
+	case at < n.left.length:
+		left, right := split(n.left, at)
+		return left, concat(right, n.right)
+	default:
+		left, right := split(n.right, at-n.left.length)
+		return concat(n.left, left), right
+	}
+}
+
+func rebalance(n *node) *node {
+	leaves := make([]*node, 0, n.leaves)
+	collectLeaves(n, &leaves)
+	return build(leaves)
+}
+
+func collectLeaves(n *node, leaves *[]*node) {
+	if n.isLeaf() {
+		*leaves = append(*leaves, n)
+		return
+	}
+
+	collectLeaves(n.left, leaves)
+	collectLeaves(n.right, leaves)
+}
+
+func build(leaves []*node) *node {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+
+	middle := len(leaves) / 2
+	return join(build(leaves[:middle]), build(leaves[middle:]))
+}
+
+// eachLeaf calls fn for leaves in order until it returns false
+func eachLeaf(n *node, fn func(text string) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	if n.isLeaf() {
+		return fn(n.text)
+	}
+
+	return eachLeaf(n.left, fn) && eachLeaf(n.right, fn)
+}
@@ -0,0 +1,156 @@
+package rope
+
+import (
+	"io"
+	"math/bits"
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// go test -bench=. -benchmem
+
+func TestEdits(t *testing.T) {
+	rope := New("Hello world")
+
+	rope, err := rope.Insert(5, ",")
+	require.NoError(t, err)
+	rope, err = rope.Insert(rope.Len(), "!")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, world!", rope.String())
+
+	deleted, err := rope.Delete(5, 12)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello!", deleted.String())
+	assert.Equal(t, "Hello, world!", rope.String())
+
+	slice, err := rope.Slice(7, 12)
+	require.NoError(t, err)
+	assert.Equal(t, "world", slice.String())
+	assert.Equal(t, "world, Hello!", slice.Concat(New(", ")).Concat(deleted).String())
+
+	_, err = rope.Insert(14, "?")
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = rope.Delete(3, 2)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = rope.Slice(-1, 2)
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestEmpty(t *testing.T) {
+	var rope Rope
+	assert.Equal(t, 0, rope.Len())
+	assert.Equal(t, "", rope.String())
+	assert.Equal(t, 0, rope.Index(""))
+	assert.Equal(t, -1, rope.Index("a"))
+
+	_, _, err := rope.Reader().ReadRune()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestRandomEdits(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	words := []string{"a", "bc", "def", "привет", "世界", strings.Repeat("x", 700)}
+
+	var rope Rope
+	var expected string
+	for i := 0; i < 2000; i++ {
+		start := random.Intn(len(expected) + 1)
+		if random.Intn(3) == 0 && len(expected) != 0 {
+			end := start + random.Intn(len(expected)-start+1)
+
+			var err error
+			rope, err = rope.Delete(start, end)
+			require.NoError(t, err)
+			expected = expected[:start] + expected[end:]
+		} else {
+			word := words[random.Intn(len(words))]
+
+			var err error
+			rope, err = rope.Insert(start, word)
+			require.NoError(t, err)
+			expected = expected[:start] + word + expected[start:]
+		}
+
+		require.Equal(t, len(expected), rope.Len())
+	}
+
+	assert.Equal(t, expected, rope.String())
+	assert.LessOrEqual(t, rope.root.depth, 2*bits.Len(uint(rope.root.leaves))+2)
+
+	for _, substr := range []string{"привет世", "cde", "xa", "a", "zzz"} {
+		assert.Equal(t, strings.Index(expected, substr), rope.Index(substr), substr)
+	}
+
+	data, err := io.ReadAll(rope.Reader())
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(data))
+}
+
+func TestIndexAcrossLeaves(t *testing.T) {
+	rope := New(strings.Repeat("a", 600)).
+		Concat(New("b" + strings.Repeat("c", 600))).
+		Concat(New("d"))
+
+	assert.Equal(t, 598, rope.Index("aabc"))
+	assert.Equal(t, 1200, rope.Index("cd"))
+	assert.Equal(t, 1201, rope.Index("d"))
+	assert.Equal(t, -1, rope.Index("ad"))
+}
+
+func TestReaderRunes(t *testing.T) {
+	text := "héllo, 世界! \xff end"
+
+	// split every multibyte rune between leaves
+	var rope Rope
+	for idx := 0; idx < len(text); idx++ {
+		rope = rope.Concat(Rope{root: &node{text: text[idx : idx+1], length: 1, leaves: 1}})
+	}
+
+	var runes []rune
+	reader := rope.Reader()
+	for {
+		value, size, err := reader.ReadRune()
+		if err == io.EOF {
+			break
+		}
+
+		require.NoError(t, err)
+		assert.True(t, utf8.RuneLen(value) == size || value == utf8.RuneError)
+		runes = append(runes, value)
+	}
+
+	assert.Equal(t, []rune(text), runes)
+}
+
+func TestStringIsCached(t *testing.T) {
+	rope := New(strings.Repeat("a", 600)).Concat(New(strings.Repeat("b", 600)))
+	first := rope.String()
+	second := rope.String()
+	assert.Same(t, unsafe.StringData(first), unsafe.StringData(second))
+}
+
+const benchmarkSize = 4 << 20
+
+func BenchmarkInsertIntoSlice(b *testing.B) {
+	text := []byte(strings.Repeat("log line\n", benchmarkSize/9))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		position := (i * 7919) % len(text)
+		text = append(text[:position], append([]byte("inserted"), text[position:]...)...)
+	}
+}
+
+func BenchmarkInsertIntoRope(b *testing.B) {
+	rope := New(strings.Repeat("log line\n", benchmarkSize/9))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		position := (i * 7919) % rope.Len()
+		rope, _ = rope.Insert(position, "inserted")
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ io.Writer       = (*Builder)(nil)
+	_ io.ByteWriter   = (*Builder)(nil)
+	_ io.StringWriter = (*Builder)(nil)
+)
+
+type writer interface {
+	io.Writer
+	io.ByteWriter
+	io.StringWriter
+	WriteRune(rune) (int, error)
+	Grow(int)
+	Len() int
+	String() string
+}
+
+func writeAll(w writer) []int {
+	var sizes []int
+	w.Grow(4)
+
+	size, _ := w.Write([]byte("bytes "))
+	sizes = append(sizes, size)
+	_ = w.WriteByte('!')
+
+	for _, symbol := range []rune{'a', 'ж', '世', '🙂', -1, utf8.MaxRune + 1, 0xD800} {
+		size, _ = w.WriteRune(symbol)
+		sizes = append(sizes, size)
+	}
+
+	size, _ = w.WriteString(" строка")
+	sizes = append(sizes, size, w.Len())
+	return sizes
+}
+
+func TestBuilderLikeStringsBuilder(t *testing.T) {
+	var expected strings.Builder
+	var actual Builder
+
+	assert.Equal(t, writeAll(&expected), writeAll(&actual))
+	assert.Equal(t, expected.String(), actual.String())
+	assert.GreaterOrEqual(t, actual.Cap(), actual.Len())
+}
+
+func TestBuilderZeroCopyString(t *testing.T) {
+	var builder Builder
+	_, _ = builder.WriteString("hello")
+
+	first := builder.String()
+	assert.Same(t, unsafe.SliceData(builder.buffer), unsafe.StringData(first))
+
+	// written bytes are never changed
+	_, _ = builder.WriteString(" world")
+	assert.Equal(t, "hello", first)
+
+	builder.Reset()
+	assert.Equal(t, 0, builder.Len())
+	_, _ = builder.WriteString("HELLO")
+	assert.Equal(t, "hello", first)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = builder.String()
+	})
+
+	assert.Zero(t, allocs)
+}
+
+func TestBuilderAt(t *testing.T) {
+	var builder Builder
+	_ = builder.WriteByte('a')
+
+	value, found := builder.At(0)
+	assert.True(t, found)
+	assert.Equal(t, byte('a'), value)
+
+	_, found = builder.At(1)
+	assert.False(t, found)
+}
+
+func TestBuilderCopyCheck(t *testing.T) {
+	var empty Builder
+	copied := empty
+	_ = copied.WriteByte('a')
+
+	var builder Builder
+	_ = builder.WriteByte('a')
+	copied = builder
+
+	assert.Panics(t, func() { _ = copied.WriteByte('b') })
+	assert.Panics(t, func() { copied.Grow(10) })
+
+	copied.Reset()
+	assert.NotPanics(t, func() { _ = copied.WriteByte('b') })
+}
+
+func TestBuilderDoesNotEscape(t *testing.T) {
+	// only the buffer is allocated, the builder stays on the stack
+	allocs := testing.AllocsPerRun(100, func() {
+		var builder Builder
+		builder.Grow(16)
+		_, _ = builder.Write([]byte("hello"))
+		_ = builder.WriteByte(' ')
+		_, _ = builder.WriteRune('ж')
+		_, _ = builder.WriteString("!")
+	})
+
+	assert.Equal(t, 1.0, allocs)
+}
@@ -1,8 +1,17 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"unicode/utf8"
+	"unsafe"
+)
 
+// Builder doesn't change written bytes, so String can return
+// them without copying, the same as strings.Builder does
 type Builder struct {
+	// builder copied after the first write shares its buffer
+	// with the source, so writes to them overwrite each other
+	addr   *Builder
 	buffer []byte
 }
 
@@ -10,44 +19,104 @@ func NewBuilder() Builder {
 	return Builder{}
 }
 
-func (b *Builder) Grow(capacity int) {
-	if capacity < 0 {
+// Grow guarantees space for another count bytes
+// without reallocation, buffer is never shrunk
+func (b *Builder) Grow(count int) {
+	b.copyCheck()
+	if count < 0 || cap(b.buffer)-len(b.buffer) >= count {
 		return
 	}
 
-	if capacity < len(b.buffer) {
-		b.buffer = b.buffer[:capacity]
-		return
-	}
-
-	buffer := make([]byte, len(b.buffer), capacity)
+	buffer := make([]byte, len(b.buffer), 2*cap(b.buffer)+count)
 	copy(buffer, b.buffer)
 	b.buffer = buffer
 }
 
-func (b *Builder) Write(symbol byte) {
+func (b *Builder) Len() int {
+	return len(b.buffer)
+}
+
+func (b *Builder) Cap() int {
+	return cap(b.buffer)
+}
+
+// Reset doesn't reuse buffer, it can be referenced by strings
+func (b *Builder) Reset() {
+	b.addr = nil
+	b.buffer = nil
+}
+
+func (b *Builder) Write(data []byte) (int, error) {
+	b.copyCheck()
+	b.buffer = append(b.buffer, data...)
+	return len(data), nil
+}
+
+func (b *Builder) WriteByte(symbol byte) error {
+	b.copyCheck()
 	b.buffer = append(b.buffer, symbol)
+	return nil
+}
+
+// WriteRune writes utf8.RuneError for invalid runes
+func (b *Builder) WriteRune(symbol rune) (int, error) {
+	b.copyCheck()
+	length := len(b.buffer)
+	b.buffer = utf8.AppendRune(b.buffer, symbol)
+	return len(b.buffer) - length, nil
+}
+
+func (b *Builder) WriteString(data string) (int, error) {
+	b.copyCheck()
+	b.buffer = append(b.buffer, data...)
+	return len(data), nil
 }
 
-func (b *Builder) At(index int) *byte {
+// At returns a byte instead of a pointer to it,
+// because it would allow changing returned strings
+func (b *Builder) At(index int) (byte, bool) {
 	if index < 0 || index >= len(b.buffer) {
-		return nil
+		return 0, false
 	}
 
-	return &b.buffer[index]
+	return b.buffer[index], true
 }
 
 func (b *Builder) String() string {
-	return string(b.buffer)
+	return unsafe.String(unsafe.SliceData(b.buffer), len(b.buffer))
+}
+
+func (b *Builder) copyCheck() {
+	if b.addr == nil {
+		// storing b in itself would move every builder to the heap,
+		// so the pointer is hidden from escape analysis (as in strings.Builder)
+		b.addr = (*Builder)(noescape(unsafe.Pointer(b)))
+	} else if b.addr != b {
+		panic("illegal use of non-zero Builder copied by value")
+	}
+}
+
+// noescape hides a pointer from escape analysis, the same as
+// abi.NoEscape, the address is read back from an uintptr variable,
+// because vet rejects arithmetic on it (x ^ 0 of the runtime).
+// Stack can't move between the conversions, because there are
+// no calls, but checkptr instrumentation would add one
+//
+//go:nocheckptr
+func noescape(pointer unsafe.Pointer) unsafe.Pointer {
+	address := uintptr(pointer)
+	return *(*unsafe.Pointer)(unsafe.Pointer(&address))
 }
 
 func main() {
 	builder := NewBuilder()
 	builder.Grow(3)
 
-	builder.Write('a')
-	builder.Write('b')
-	builder.Write('c')
+	_ = builder.WriteByte('a')
+	_ = builder.WriteByte('b')
+	_ = builder.WriteByte('c')
+	_, _ = builder.WriteRune('д')
+	_, _ = fmt.Fprintf(&builder, " %d", 42)
 
-	fmt.Println(builder.String())
+	fmt.Println(builder.String(), builder.Len(), builder.Cap())
 }
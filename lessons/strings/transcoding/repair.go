@@ -0,0 +1,130 @@
+// Package transcoding validates and repairs UTF-8 streams and transcodes
+// them from/to UTF-16 and single-byte Cyrillic encodings
+package transcoding
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// Policy of repairing invalid bytes
+type Policy int
+
+const (
+	Drop    Policy = iota
+	Replace        // with U+FFFD
+	Escape         // as \xNN
+)
+
+// InvalidByte is reported for every byte that isn't a part of a valid
+// UTF-8 sequence, like the range loop over strings decodes them
+type InvalidByte struct {
+	Offset int64
+	Value  byte
+}
+
+func (b InvalidByte) String() string {
+	return fmt.Sprintf(`\x%02X at %d`, b.Value, b.Offset)
+}
+
+// Validate reads the whole stream and returns all invalid bytes
+func Validate(reader io.Reader) ([]InvalidByte, error) {
+	var invalid []InvalidByte
+	repairer := &repairer{
+		policy: Drop,
+		report: func(value InvalidByte) {
+			invalid = append(invalid, value)
+		},
+	}
+
+	if _, err := io.Copy(io.Discard, transform.NewReader(reader, repairer)); err != nil {
+		return nil, err
+	}
+
+	return invalid, nil
+}
+
+// NewRepairReader returns valid UTF-8 repairing invalid bytes by policy
+func NewRepairReader(reader io.Reader, policy Policy) io.Reader {
+	return transform.NewReader(reader, &repairer{policy: policy})
+}
+
+// repairer is a transformer, so sequences split between
+// reads of the source are handled by transform.Reader
+type repairer struct {
+	policy Policy
+	report func(InvalidByte)
+	offset int64 // of the beginning of src
+}
+
+func (r *repairer) Transform(dst, src []byte, atEOF bool) (int, int, error) {
+	var nDst, nSrc int
+	var err error
+	for nSrc < len(src) {
+		size := 1
+		if src[nSrc] >= utf8.RuneSelf {
+			var value rune
+			value, size = utf8.DecodeRune(src[nSrc:])
+			if value == utf8.RuneError && size == 1 {
+				if !atEOF && !utf8.FullRune(src[nSrc:]) {
+					err = transform.ErrShortSrc
+					break
+				}
+
+				written, ok := r.repair(dst[nDst:], src[nSrc])
+				if !ok {
+					err = transform.ErrShortDst
+					break
+				}
+
+				if r.report != nil {
+					r.report(InvalidByte{Offset: r.offset + int64(nSrc), Value: src[nSrc]})
+				}
+
+				nDst += written
+				nSrc++
+				continue
+			}
+		}
+
+		if len(dst)-nDst < size {
+			err = transform.ErrShortDst
+			break
+		}
+
+		nDst += copy(dst[nDst:], src[nSrc:nSrc+size])
+		nSrc += size
+	}
+
+	r.offset += int64(nSrc)
+	return nDst, nSrc, err
+}
+
+func (r *repairer) Reset() {
+	r.offset = 0
+}
+
+// repair writes replacement of value, it returns false if dst is too short
+func (r *repairer) repair(dst []byte, value byte) (int, bool) {
+	const hex = "0123456789ABCDEF"
+
+	switch r.policy {
+	case Replace:
+		if len(dst) < utf8.RuneLen(utf8.RuneError) {
+			return 0, false
+		}
+
+		return utf8.EncodeRune(dst, utf8.RuneError), true
+	case Escape:
+		if len(dst) < 4 {
+			return 0, false
+		}
+
+		return copy(dst, []byte{'\\', 'x', hex[value>>4], hex[value&0xF]}), true
+	default:
+		return 0, true
+	}
+}
@@ -0,0 +1,63 @@
+package transcoding
+
+import (
+	"errors"
+	"io"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+type Encoding string
+
+const (
+	UTF8        Encoding = "utf-8"
+	UTF16LE     Encoding = "utf-16le"
+	UTF16BE     Encoding = "utf-16be"
+	Windows1251 Encoding = "windows-1251"
+	KOI8R       Encoding = "koi8-r"
+)
+
+var ErrUnknownEncoding = errors.New("unknown encoding")
+
+var encodings = map[Encoding]encoding.Encoding{
+	UTF8:        unicode.UTF8,
+	UTF16LE:     unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM),
+	UTF16BE:     unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM),
+	Windows1251: charmap.Windows1251,
+	KOI8R:       charmap.KOI8R,
+}
+
+// NewDecoder converts text from the encoding to UTF-8,
+// invalid sequences are replaced with U+FFFD
+func NewDecoder(reader io.Reader, from Encoding) (io.Reader, error) {
+	textEncoding, found := encodings[from]
+	if !found {
+		return nil, ErrUnknownEncoding
+	}
+
+	return transform.NewReader(reader, textEncoding.NewDecoder()), nil
+}
+
+// NewEncoder converts UTF-8 text to the encoding, reading fails
+// on runes that can't be represented in the encoding (like
+// Chinese in KOI8-R), they aren't silently replaced
+func NewEncoder(reader io.Reader, to Encoding) (io.Reader, error) {
+	textEncoding, found := encodings[to]
+	if !found {
+		return nil, ErrUnknownEncoding
+	}
+
+	return transform.NewReader(reader, textEncoding.NewEncoder()), nil
+}
+
+func NewTranscoder(reader io.Reader, from, to Encoding) (io.Reader, error) {
+	decoder, err := NewDecoder(reader, from)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewEncoder(decoder, to)
+}
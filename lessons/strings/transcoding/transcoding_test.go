@@ -0,0 +1,93 @@
+package transcoding
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// "é" split into two reads, truncated "€" and invalid bytes
+const invalidText = "caf\xc3\xa9 \xe2\x82 \xff\xfeok"
+
+func TestValidate(t *testing.T) {
+	invalid, err := Validate(iotest.OneByteReader(strings.NewReader(invalidText)))
+	require.NoError(t, err)
+
+	assert.Equal(t, []InvalidByte{
+		{Offset: 6, Value: 0xe2},
+		{Offset: 7, Value: 0x82},
+		{Offset: 9, Value: 0xff},
+		{Offset: 10, Value: 0xfe},
+	}, invalid)
+	assert.Equal(t, `\xE2 at 6`, invalid[0].String())
+
+	invalid, err = Validate(strings.NewReader("valid текст �"))
+	require.NoError(t, err)
+	assert.Empty(t, invalid)
+}
+
+func TestRepair(t *testing.T) {
+	tests := map[Policy]string{
+		Drop:    "café  ok",
+		Replace: "café �� ��ok",
+		Escape:  `café \xE2\x82 \xFF\xFEok`,
+	}
+
+	for policy, expected := range tests {
+		reader := NewRepairReader(iotest.HalfReader(strings.NewReader(invalidText)), policy)
+		repaired, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(repaired))
+	}
+}
+
+func TestRepairLargeStream(t *testing.T) {
+	text := strings.Repeat("текст\xff", 10000)
+	repaired, err := io.ReadAll(NewRepairReader(strings.NewReader(text), Escape))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat(`текст\xFF`, 10000), string(repaired))
+}
+
+func transcode(t *testing.T, data []byte, from, to Encoding) []byte {
+	reader, err := NewTranscoder(iotest.OneByteReader(bytes.NewReader(data)), from, to)
+	require.NoError(t, err)
+
+	result, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return result
+}
+
+func TestTranscode(t *testing.T) {
+	text := []byte("Привет")
+	encoded := map[Encoding][]byte{
+		UTF8:        text,
+		UTF16LE:     {0x1f, 0x04, 0x40, 0x04, 0x38, 0x04, 0x32, 0x04, 0x35, 0x04, 0x42, 0x04},
+		UTF16BE:     {0x04, 0x1f, 0x04, 0x40, 0x04, 0x38, 0x04, 0x32, 0x04, 0x35, 0x04, 0x42},
+		Windows1251: {0xcf, 0xf0, 0xe8, 0xe2, 0xe5, 0xf2},
+		KOI8R:       {0xf0, 0xd2, 0xc9, 0xd7, 0xc5, 0xd4},
+	}
+
+	for from, source := range encoded {
+		for to, expected := range encoded {
+			assert.Equal(t, expected, transcode(t, source, from, to), "%s -> %s", from, to)
+		}
+	}
+}
+
+func TestTranscodeErrors(t *testing.T) {
+	_, err := NewDecoder(strings.NewReader(""), "cp866")
+	assert.ErrorIs(t, err, ErrUnknownEncoding)
+
+	// invalid UTF-8 is replaced by decoder
+	assert.Equal(t, "a�b", string(transcode(t, []byte("a\xffb"), UTF8, UTF8)))
+
+	reader, err := NewEncoder(strings.NewReader("мир 世界"), KOI8R)
+	require.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.Error(t, err)
+}
@@ -0,0 +1,23 @@
+// Package zerocopy converts between strings and byte slices without
+// copying. Bytes converted to a string mustn't be changed while the
+// string is alive, and bytes of a converted string mustn't be changed
+// at all. Build with -tags zerocopydebug to check it at runtime.
+package zerocopy
+
+import "unsafe"
+
+func bytesToString(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	return unsafe.String(unsafe.SliceData(data), len(data))
+}
+
+func stringToBytes(str string) []byte {
+	if len(str) == 0 {
+		return nil
+	}
+
+	return unsafe.Slice(unsafe.StringData(str), len(str))
+}
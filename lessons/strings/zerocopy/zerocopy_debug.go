@@ -0,0 +1,135 @@
+//go:build zerocopydebug
+
+package zerocopy
+
+import (
+	"fmt"
+	"hash/maphash"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// Debug reports whether runtime checks are enabled
+const Debug = true
+
+// memory of the oldest conversions stops being
+// checked when there are more conversions
+const maxTracked = 1 << 16
+
+// liveness of strings can't be tracked, so conversions are checked
+// by Verify and Release until they are released, replaced by a newer
+// conversion of the same memory or evicted by newer conversions
+type conversion struct {
+	data     []byte // keeps memory alive
+	checksum uint64
+	caller   string
+	sequence int
+}
+
+type key struct {
+	pointer *byte
+	length  int
+}
+
+type trackedKey struct {
+	key
+	sequence int
+}
+
+var registry = struct {
+	sync.Mutex
+	conversions map[key]conversion
+	order       []trackedKey
+	sequence    int
+}{
+	conversions: make(map[key]conversion),
+}
+
+var seed = maphash.MakeSeed()
+
+// BytesToString tracks data until the string is released, converting
+// the same memory again replaces the previous conversion, because
+// strings of temporary conversions (like map lookups) aren't released
+func BytesToString(data []byte) string {
+	track(data)
+	return bytesToString(data)
+}
+
+// StringToBytes tracks str until it's released, memory of string
+// literals is read-only, so writing to it crashes the program
+func StringToBytes(str string) []byte {
+	data := stringToBytes(str)
+	track(data)
+	return data
+}
+
+// Release stops tracking str, it panics if its memory was changed.
+// Release is required when str isn't used anymore, otherwise the next
+// change of its memory is reported by Verify
+func Release(str string) {
+	if len(str) == 0 {
+		return
+	}
+
+	registry.Lock()
+	conversionKey := key{unsafe.StringData(str), len(str)}
+	conversion, found := registry.conversions[conversionKey]
+	delete(registry.conversions, conversionKey)
+	registry.Unlock()
+
+	if found {
+		conversion.verify()
+	}
+}
+
+// Verify panics if memory of any tracked conversion was changed,
+// it hashes all tracked memory, so it isn't called by conversions
+func Verify() {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, conversion := range registry.conversions {
+		conversion.verify()
+	}
+}
+
+func (c conversion) verify() {
+	if maphash.Bytes(seed, c.data) != c.checksum {
+		panic(fmt.Sprintf("zerocopy: memory of conversion at %s was changed: %q", c.caller, c.data))
+	}
+}
+
+func track(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+
+	caller := "unknown"
+	if _, file, line, ok := runtime.Caller(2); ok {
+		caller = fmt.Sprintf("%s:%d", file, line)
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.sequence++
+	conversionKey := key{unsafe.SliceData(data), len(data)}
+	registry.conversions[conversionKey] = conversion{
+		data:     data,
+		checksum: maphash.Bytes(seed, data),
+		caller:   caller,
+		sequence: registry.sequence,
+	}
+
+	registry.order = append(registry.order, trackedKey{conversionKey, registry.sequence})
+	if len(registry.order) > maxTracked {
+		// the same memory can be converted again later
+		oldest := registry.order[0]
+		if registry.conversions[oldest.key].sequence == oldest.sequence {
+			delete(registry.conversions, oldest.key)
+		}
+
+		registry.order = registry.order[1:]
+	}
+}
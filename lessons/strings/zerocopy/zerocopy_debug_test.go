@@ -0,0 +1,66 @@
+//go:build zerocopydebug
+
+package zerocopy
+
+import (
+	"fmt"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func panicMessage(fn func()) (message string) {
+	defer func() {
+		message = fmt.Sprint(recover())
+	}()
+
+	fn()
+	return ""
+}
+
+func TestMutationPanics(t *testing.T) {
+	data := []byte("hello")
+	str := BytesToString(data)
+
+	data[0] = 'j'
+	assert.Contains(t, panicMessage(Verify), "zerocopy_debug_test.go")
+	assert.Contains(t, panicMessage(Verify), `was changed: "jello"`)
+	assert.NotPanics(t, func() { _ = BytesToString([]byte("other")) })
+
+	assert.Panics(t, func() { Release(str) })
+	assert.NotPanics(t, Verify)
+}
+
+func TestReleasedMemoryCanBeChanged(t *testing.T) {
+	data := []byte("hello")
+	Release(BytesToString(data))
+
+	data[0] = 'j'
+	assert.NotPanics(t, Verify)
+}
+
+func TestStringMutationPanics(t *testing.T) {
+	str := string([]byte("hello"))
+	data := StringToBytes(str)
+
+	data[0] = 'j'
+	assert.Panics(t, Verify)
+	assert.Panics(t, func() { Release(str) })
+	assert.NotPanics(t, Verify)
+}
+
+func TestConversionOfSameMemoryReplacesPrevious(t *testing.T) {
+	data := []byte("hello")
+	counts := map[string]int{"hello": 1, "jello": 2}
+
+	assert.Equal(t, 1, counts[BytesToString(data)])
+	data[0] = 'j'
+	assert.Equal(t, 2, counts[BytesToString(data)])
+	assert.NotPanics(t, Verify)
+
+	data[0] = 'c'
+	assert.Panics(t, Verify)
+	assert.Panics(t, func() { Release(unsafe.String(&data[0], len(data))) })
+	assert.NotPanics(t, Verify)
+}
@@ -0,0 +1,23 @@
+//go:build !zerocopydebug
+
+package zerocopy
+
+// Debug reports whether runtime checks are enabled
+const Debug = false
+
+func BytesToString(data []byte) string {
+	return bytesToString(data)
+}
+
+// StringToBytes returns read-only bytes, memory of string
+// literals is read-only, so writing to it crashes the program
+func StringToBytes(str string) []byte {
+	return stringToBytes(str)
+}
+
+// Release tells that str (or bytes converted from it) isn't used anymore,
+// it's required in debug mode to change memory of the conversion later
+func Release(string) {}
+
+// Verify checks that converted memory wasn't changed
+func Verify() {}
@@ -0,0 +1,53 @@
+package zerocopy
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -v -tags zerocopydebug .
+
+func TestBytesToString(t *testing.T) {
+	data := []byte("hello")
+	str := BytesToString(data)
+
+	assert.Equal(t, "hello", str)
+	assert.Same(t, unsafe.SliceData(data), unsafe.StringData(str))
+	assert.Equal(t, "", BytesToString(nil))
+	assert.Equal(t, "", BytesToString([]byte{}))
+	Release(str)
+}
+
+func TestStringToBytes(t *testing.T) {
+	str := string([]byte("hello"))
+	data := StringToBytes(str)
+
+	assert.Equal(t, []byte("hello"), data)
+	assert.Same(t, unsafe.StringData(str), unsafe.SliceData(data))
+	assert.Nil(t, StringToBytes(""))
+	Release(str)
+}
+
+func TestZeroAllocations(t *testing.T) {
+	if Debug {
+		t.Skip("conversions are tracked in debug mode")
+	}
+
+	data := []byte("hello world")
+	str := "hello world"
+
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		_ = BytesToString(data)
+	}))
+
+	assert.Zero(t, testing.AllocsPerRun(100, func() {
+		_ = StringToBytes(str)
+	}))
+
+	// the standard conversion copies
+	assert.NotZero(t, testing.AllocsPerRun(100, func() {
+		str = string(data)
+	}))
+}
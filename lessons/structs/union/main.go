@@ -1,6 +1,9 @@
 package main
 
-import "unsafe"
+import (
+	"fmt"
+	"unsafe"
+)
 
 // small buffer optimization
 type SBO struct {
@@ -15,9 +18,17 @@ func main() {
 	var big SBO
 	big.size = 1024
 
+	// pointer inside of bytes is invisible for GC, so the
+	// array can be collected, see SmallBytes for safe version
 	pointer := (**[1024]byte)(unsafe.Pointer(&big.union))
 	*(pointer) = new([1024]byte)
 
 	capacity := (*int64)(unsafe.Add(unsafe.Pointer(&big.union), 8))
 	*(capacity) = 2048
+
+	key := SmallString("user:1234")
+	fmt.Println(key.String(), key.Len(), key.Cap(), key.Inline())
+
+	key.Append([]byte(":profile:settings")...)
+	fmt.Println(key.String(), key.Len(), key.Cap(), key.Inline())
 }
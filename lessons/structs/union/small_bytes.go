@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"unsafe"
+)
+
+const (
+	unionSize      = 16
+	inlineCapacity = unionSize - 1 // the last byte is length
+)
+
+// SmallBytes stores up to 15 bytes inline and spills to the heap beyond
+// that, pointer to the heap is stored in a separate field (not in the
+// union like in SBO), because GC doesn't see pointers inside of bytes
+//
+// inline: heap == nil, union = [15 bytes of data][1 byte of length]
+// heap:   heap != nil, union = [8 bytes of length][8 bytes of capacity]
+type SmallBytes struct {
+	heap  *byte
+	union [unionSize]byte
+}
+
+func NewSmallBytes(data []byte) SmallBytes {
+	var small SmallBytes
+	small.Append(data...)
+	return small
+}
+
+func SmallString(data string) SmallBytes {
+	var small SmallBytes
+	small.AppendString(data)
+	return small
+}
+
+func (s *SmallBytes) Inline() bool {
+	return s.heap == nil
+}
+
+func (s *SmallBytes) Len() int {
+	if s.Inline() {
+		return int(s.union[inlineCapacity])
+	}
+
+	return int(binary.NativeEndian.Uint64(s.union[:8]))
+}
+
+func (s *SmallBytes) Cap() int {
+	if s.Inline() {
+		return inlineCapacity
+	}
+
+	return int(binary.NativeEndian.Uint64(s.union[8:]))
+}
+
+// Bytes returns data without copying, inline data is stored inside
+// of s, so the slice is valid only until the next Append
+func (s *SmallBytes) Bytes() []byte {
+	if s.Inline() {
+		length := s.Len()
+		return s.union[:length:length]
+	}
+
+	return unsafe.Slice(s.heap, s.Cap())[:s.Len()]
+}
+
+func (s *SmallBytes) String() string {
+	return string(s.Bytes())
+}
+
+func (s *SmallBytes) Equal(other *SmallBytes) bool {
+	if s.Inline() && other.Inline() {
+		// bytes after data are always zero
+		return s.union == other.union
+	}
+
+	return bytes.Equal(s.Bytes(), other.Bytes())
+}
+
+func (s *SmallBytes) Append(data ...byte) {
+	length := s.Len()
+	if length+len(data) <= s.Cap() {
+		if s.Inline() {
+			copy(s.union[length:], data)
+			s.union[inlineCapacity] = byte(length + len(data))
+		} else {
+			copy(unsafe.Slice(s.heap, s.Cap())[length:], data)
+			s.setHeap(s.heap, length+len(data), s.Cap())
+		}
+
+		return
+	}
+
+	// spill or grow twice, buffer isn't created by append from
+	// Bytes, otherwise s always escapes as the inline array can
+	// be stored to the heap pointer
+	buffer := make([]byte, length+len(data), max(2*s.Cap(), length+len(data)))
+	copy(buffer, s.Bytes())
+	copy(buffer[length:], data)
+
+	s.union = [unionSize]byte{}
+	s.setHeap(unsafe.SliceData(buffer), len(buffer), cap(buffer))
+}
+
+// AppendString appends data without converting it to a slice
+func (s *SmallBytes) AppendString(data string) {
+	s.Append(unsafe.Slice(unsafe.StringData(data), len(data))...)
+}
+
+func (s *SmallBytes) setHeap(pointer *byte, length int, capacity int) {
+	s.heap = pointer
+	binary.NativeEndian.PutUint64(s.union[:8], uint64(length))
+	binary.NativeEndian.PutUint64(s.union[8:], uint64(capacity))
+}
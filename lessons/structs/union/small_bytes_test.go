@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// go test -bench=. -benchmem
+
+func TestSmallBytesSize(t *testing.T) {
+	var small SmallBytes
+	var slice []byte
+	assert.Equal(t, unsafe.Sizeof(slice), unsafe.Sizeof(small))
+}
+
+func TestSmallBytesInline(t *testing.T) {
+	var small SmallBytes
+	assert.Equal(t, 0, small.Len())
+	assert.Equal(t, "", small.String())
+
+	small.Append([]byte("hello ")...)
+	small.Append([]byte("world!!!!")...)
+	assert.True(t, small.Inline())
+	assert.Equal(t, "hello world!!!!", small.String())
+	assert.Equal(t, inlineCapacity, small.Len())
+	assert.Equal(t, inlineCapacity, small.Cap())
+
+	// appending to returned slice mustn't overwrite length
+	short := SmallString("abc")
+	assert.Equal(t, "abc", string(append(short.Bytes(), 'd')[:3]))
+	assert.Equal(t, 3, short.Len())
+}
+
+func TestSmallBytesSpill(t *testing.T) {
+	small := SmallString("hello world!!!!")
+	small.Append('?')
+	assert.False(t, small.Inline())
+	assert.Equal(t, "hello world!!!!?", small.String())
+	assert.GreaterOrEqual(t, small.Cap(), small.Len())
+
+	for i := 0; i < 100; i++ {
+		small.Append('a' + byte(i%26))
+	}
+
+	assert.Equal(t, 116, small.Len())
+	assert.Equal(t, "hello world!!!!?abc", small.String()[:19])
+}
+
+func TestSmallBytesCopy(t *testing.T) {
+	small := SmallString("short")
+	copied := small
+	copied.Append('!')
+
+	assert.Equal(t, "short", small.String())
+	assert.Equal(t, "short!", copied.String())
+}
+
+func TestSmallBytesEqual(t *testing.T) {
+	inline := SmallString("key")
+	other := NewSmallBytes([]byte("key"))
+	assert.True(t, inline.Equal(&other))
+
+	long := SmallString("a very long key that doesn't fit")
+	otherLong := SmallString("a very long key that doesn't fit")
+	assert.True(t, long.Equal(&otherLong))
+	assert.False(t, long.Equal(&inline))
+}
+
+const keysNumber = 1024
+
+var keys = func() map[string]int {
+	keys := make(map[string]int, keysNumber)
+	for i := 0; i < keysNumber; i++ {
+		keys["user:"+strconv.Itoa(i)] = i
+	}
+
+	return keys
+}()
+
+func BenchmarkMapKeysWithSlice(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var key []byte
+		key = append(key, "user:"...)
+		key = strconv.AppendInt(key, int64(i%keysNumber), 10)
+		_ = keys[string(key)]
+	}
+}
+
+func BenchmarkMapKeysWithSmallBytes(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var key SmallBytes
+		key.AppendString("user:")
+		key.Append(strconv.AppendInt(make([]byte, 0, 8), int64(i%keysNumber), 10)...)
+		_ = keys[string(key.Bytes())]
+	}
+}
+
+func BenchmarkEqualWithSlice(b *testing.B) {
+	left, right := []byte("user:1234"), []byte("user:1234")
+	for i := 0; i < b.N; i++ {
+		_ = bytes.Equal(left, right)
+	}
+}
+
+func BenchmarkEqualWithSmallBytes(b *testing.B) {
+	left, right := SmallString("user:1234"), SmallString("user:1234")
+	for i := 0; i < b.N; i++ {
+		_ = left.Equal(&right)
+	}
+}